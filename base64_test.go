@@ -0,0 +1,54 @@
+package envstruct_test
+
+import (
+	"encoding/base64"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestBase64Option() {
+	type Config struct {
+		Cert    string            `tag:"cert,base64"`
+		Key     []byte            `tag:"key,base64,secret"`
+		Workers int               `tag:"workers,base64"`
+		Labels  map[string]string `tag:"labels,base64"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("decodes the value before it is parsed", func() {
+		os.Setenv("APP_CERT", base64.StdEncoding.EncodeToString([]byte("line 1\nline 2\n")))
+		os.Setenv("APP_KEY", base64.RawURLEncoding.EncodeToString([]byte{0xfb, 0xff, 0x00}))
+		os.Setenv("APP_WORKERS", base64.StdEncoding.EncodeToString([]byte("4")))
+		os.Setenv("APP_LABELS", base64.StdEncoding.EncodeToString([]byte("team:infra,tier:1")))
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Cert:    "line 1\nline 2\n",
+			Key:     []byte{0xfb, 0xff, 0x00},
+			Workers: 4,
+			Labels:  map[string]string{"team": "infra", "tier": "1"},
+		}, config)
+	})
+
+	s.Run("fails on values that are not base64", func() {
+		os.Clearenv()
+		os.Setenv("APP_WORKERS", "not base64!")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field Workers (env APP_WORKERS): invalid base64: illegal base64 data at input byte 9")
+	})
+}
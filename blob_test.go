@@ -0,0 +1,88 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestBlob() {
+	type Limit struct {
+		Requests int      `yaml:"requests" json:"requests"`
+		Burst    int      `yaml:"burst" json:"burst"`
+		Paths    []string `yaml:"paths" json:"paths"`
+	}
+
+	type Config struct {
+		Host   string `tag:"host"`
+		Limits struct {
+			Default Limit            `yaml:"default" json:"default"`
+			Routes  map[string]Limit `yaml:"routes" json:"routes"`
+		} `tag:"limits,blob"`
+		Override *Limit `tag:"override,blob"`
+	}
+
+	defer os.Clearenv()
+
+	s.Run("populates the struct from a YAML document", func() {
+		os.Setenv("APP_HOST", "localhost")
+		os.Setenv("APP_LIMITS", `
+default: {requests: 100, burst: 10}
+routes:
+  upload: {requests: 5, paths: [/upload, /import]}
+`)
+
+		env := envstruct.Envstruct{
+			Prefix:  "app",
+			TagName: "tag",
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("localhost", config.Host)
+		s.Equal(Limit{Requests: 100, Burst: 10}, config.Limits.Default)
+		s.Equal(map[string]Limit{"upload": {Requests: 5, Paths: []string{"/upload", "/import"}}}, config.Limits.Routes)
+		s.Nil(config.Override)
+	})
+
+	s.Run("populates the struct from a JSON document with the built in unmarshaler", func() {
+		os.Clearenv()
+		os.Setenv("APP_LIMITS", `{"default": {"requests": 100}}`)
+		os.Setenv("APP_OVERRIDE", `{"burst": 1}`)
+
+		env := envstruct.Envstruct{
+			Prefix:  "app",
+			TagName: "tag",
+
+			Parser: envstruct.Parser{Unmarshaler: envstruct.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(100, config.Limits.Default.Requests)
+		s.Equal(&Limit{Burst: 1}, config.Override)
+	})
+
+	s.Run("fails on an invalid document", func() {
+		os.Clearenv()
+		os.Setenv("APP_LIMITS", `{"default":`)
+
+		env := envstruct.Envstruct{
+			Prefix:  "app",
+			TagName: "tag",
+
+			Parser: envstruct.Parser{Unmarshaler: envstruct.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.Error(err)
+	})
+}
@@ -0,0 +1,73 @@
+package envstruct
+
+// Chain returns a Source with the name that looks up each env in the sources
+// in order, with the first source that has the env set being used, the same as
+// the Sources of an Envstruct. This allows a layered stack of sources to be
+// treated as a single one, for ex. so that fields can be pinned to it through
+// the SourceTagName, or so that the same stack can be shared between
+// Envstructs. Envs that are set to an empty string are treated as not set.
+func Chain(name string, sources ...Source) Source {
+	return chain{name: name, sources: sources}
+}
+
+type chain struct {
+	name    string
+	sources []Source
+}
+
+func (c chain) Name() string { return c.name }
+
+func (c chain) Lookup(envName string) (string, bool) {
+	value, found, _ := c.LookupErr(envName)
+	return value, found
+}
+
+func (c chain) LookupErr(envName string) (string, bool, error) {
+	for _, source := range c.sources {
+		value, found, err := lookupSource(source, envName)
+		if err != nil {
+			return "", false, err
+		}
+
+		if found && value != "" {
+			return value, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func (c chain) Names() []string {
+	var names []string
+	for _, source := range c.sources {
+		if listable, ok := source.(ListableSource); ok {
+			names = append(names, listable.Names()...)
+		}
+	}
+
+	return names
+}
+
+func (c chain) Files() []string {
+	var files []string
+	for _, source := range c.sources {
+		if fileSource, ok := source.(FileSource); ok {
+			files = append(files, fileSource.Files()...)
+		}
+	}
+
+	return files
+}
+
+func (c chain) Reload() error {
+	for _, source := range c.sources {
+		if fileSource, ok := source.(FileSource); ok {
+			err := fileSource.Reload()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
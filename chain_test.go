@@ -0,0 +1,69 @@
+package envstruct_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type failingSource struct{}
+
+func (failingSource) Name() string { return "failing" }
+
+func (failingSource) Lookup(string) (string, bool) { return "", false }
+
+func (failingSource) LookupErr(string) (string, bool, error) {
+	return "", false, errors.New("connection refused")
+}
+
+func (s *EnvstructSuite) TestChain() {
+	type Config struct {
+		Host     string `tag:"host"`
+		Password string `tag:"password" source:"secrets"`
+		Token    string `tag:"token" source:"secrets"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:        "app",
+		TagName:       "tag",
+		SourceTagName: "source",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("looks up each env in the chained sources in order", func() {
+		env := env
+		env.Sources = []envstruct.Source{
+			envstruct.Environment,
+			envstruct.Chain("secrets",
+				mapSource{"APP_PASSWORD": "", "APP_TOKEN": "from vault"},
+				mapSource{"APP_PASSWORD": "from file", "APP_TOKEN": "from file"},
+			),
+		}
+
+		os.Setenv("APP_HOST", "localhost")
+		os.Setenv("APP_PASSWORD", "from env")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Host: "localhost", Password: "from file", Token: "from vault"}, config)
+	})
+
+	s.Run("fails when one of the chained sources fails", func() {
+		env := env
+		env.Sources = []envstruct.Source{
+			envstruct.Environment,
+			envstruct.Chain("secrets", failingSource{}, mapSource{"APP_PASSWORD": "from file"}),
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "failed to look up APP_PASSWORD in source secrets: connection refused")
+	})
+}
@@ -0,0 +1,79 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EnvNames returns the name of every env that the struct can be fetched from,
+// including aliases and override names, in the order of the fields.
+func (e Envstruct) EnvNames(object interface{}) ([]string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to list envs of object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.envNames...)
+	}
+
+	return names, nil
+}
+
+// Completion renders a shell completion script that completes the names of
+// the envs of the struct as the arguments of the command, so that operators
+// get tab completion for the configuration of the application. The shell can
+// be "bash" or "zsh", and zsh also shows the description of each env.
+func (e Envstruct) Completion(object interface{}, shell string, command string) (string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return "", errors.New("failed to render completion for object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return "", err
+	}
+
+	function := "_" + strings.ToLower(flatName(command)) + "_env_names"
+
+	var script strings.Builder
+	switch shell {
+	case "bash":
+		var names []string
+		for _, f := range fields {
+			names = append(names, f.envNames...)
+		}
+
+		fmt.Fprintf(&script, "%s() {\n", function)
+		fmt.Fprintf(&script, "  COMPREPLY=($(compgen -W %s -- \"${COMP_WORDS[COMP_CWORD]}\"))\n", shellQuote(strings.Join(names, " ")))
+		fmt.Fprintf(&script, "}\n")
+		fmt.Fprintf(&script, "complete -F %s %s\n", function, command)
+
+	case "zsh":
+		fmt.Fprintf(&script, "%s() {\n", function)
+		fmt.Fprintf(&script, "  local -a names\n")
+		fmt.Fprintf(&script, "  names=(\n")
+		for _, f := range fields {
+			description := strings.ReplaceAll(e.description(f), ":", `\:`)
+			for _, name := range f.envNames {
+				fmt.Fprintf(&script, "    %s\n", shellQuote(strings.TrimSuffix(name+":"+description, ":")))
+			}
+		}
+		fmt.Fprintf(&script, "  )\n")
+		fmt.Fprintf(&script, "  _describe 'environment variable' names\n")
+		fmt.Fprintf(&script, "}\n")
+		fmt.Fprintf(&script, "compdef %s %s\n", function, command)
+
+	default:
+		return "", fmt.Errorf("unsupported shell %q, needs to be bash or zsh", shell)
+	}
+
+	return script.String(), nil
+}
@@ -0,0 +1,60 @@
+package envstruct_test
+
+import (
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestCompletion() {
+	type Config struct {
+		Port     int    `tag:"port" desc:"listen port"`
+		Address  string `tag:"addr|address" desc:"listen address, for ex. host:port"`
+		Legacy   string `tag:"legacy" override:"OLD_NAME"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:       "app",
+		TagName:      "tag",
+		OverrideName: "override",
+	}
+
+	s.Run("lists the env names", func() {
+		names, err := env.EnvNames(&Config{})
+		s.NoError(err)
+		s.Equal([]string{"APP_PORT", "APP_ADDR", "APP_ADDRESS", "OLD_NAME"}, names)
+	})
+
+	s.Run("renders bash completion", func() {
+		script, err := env.Completion(&Config{}, "bash", "my-app")
+		s.NoError(err)
+
+		s.Equal(`_my_app_env_names() {
+  COMPREPLY=($(compgen -W 'APP_PORT APP_ADDR APP_ADDRESS OLD_NAME' -- "${COMP_WORDS[COMP_CWORD]}"))
+}
+complete -F _my_app_env_names my-app
+`, script)
+	})
+
+	s.Run("renders zsh completion with descriptions", func() {
+		script, err := env.Completion(&Config{}, "zsh", "my-app")
+		s.NoError(err)
+
+		s.Equal(`_my_app_env_names() {
+  local -a names
+  names=(
+    'APP_PORT:listen port'
+    'APP_ADDR:listen address, for ex. host\:port'
+    'APP_ADDRESS:listen address, for ex. host\:port'
+    'OLD_NAME'
+  )
+  _describe 'environment variable' names
+}
+compdef _my_app_env_names my-app
+`, script)
+	})
+
+	s.Run("fails on other shells", func() {
+		_, err := env.Completion(&Config{}, "fish", "my-app")
+		s.EqualError(err, `unsupported shell "fish", needs to be bash or zsh`)
+	})
+}
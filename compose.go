@@ -0,0 +1,45 @@
+package envstruct
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ComposeEnvironment renders the current configuration of the struct as the
+// environment block of a service within a docker compose file, so that the
+// compose files for local development can be generated from the struct rather
+// than drifting from it. The envs are listed in the order that their fields
+// are declared. Any "$" within the values is escaped so that compose does not
+// interpolate it. Secrets are written as a reference to the env of the same
+// name on the host, for ex. "${APP_PASSWORD}", unless withSecrets is true.
+// Fields that are nil pointers are left out.
+func (e Envstruct) ComposeEnvironment(object interface{}, withSecrets bool) (string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return "", errors.New("failed to render compose environment for object, needs to be type struct")
+	}
+
+	values, err := e.envValues(object)
+	if err != nil {
+		return "", err
+	}
+
+	environment := yaml.MapSlice{}
+	for _, v := range values {
+		value := "${" + v.name + "}"
+		if !v.field.options.secret || withSecrets {
+			value = strings.ReplaceAll(v.value, "$", "$$")
+		}
+
+		environment = append(environment, yaml.MapItem{Key: v.name, Value: value})
+	}
+
+	block, err := yaml.Marshal(yaml.MapSlice{{Key: "environment", Value: environment}})
+	if err != nil {
+		return "", err
+	}
+
+	return string(block), nil
+}
@@ -0,0 +1,48 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestComposeEnvironment() {
+	type Config struct {
+		Port     int           `tag:"port"`
+		Timeout  time.Duration `tag:"timeout"`
+		Template string        `tag:"template"`
+		Password string        `tag:"password,secret"`
+		Token    *string       `tag:"token"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+	}
+
+	config := Config{
+		Port:     8080,
+		Timeout:  time.Minute,
+		Template: "$HOME/templates",
+		Password: "hunter2",
+	}
+
+	s.Run("renders the environment block in the order of the fields", func() {
+		block, err := env.ComposeEnvironment(&config, false)
+		s.NoError(err)
+
+		s.Equal(`environment:
+  APP_PORT: "8080"
+  APP_TIMEOUT: 1m0s
+  APP_TEMPLATE: $$HOME/templates
+  APP_PASSWORD: ${APP_PASSWORD}
+`, block)
+	})
+
+	s.Run("includes secrets if asked to", func() {
+		block, err := env.ComposeEnvironment(&config, true)
+		s.NoError(err)
+		s.Contains(block, "  APP_PASSWORD: hunter2\n")
+	})
+}
@@ -0,0 +1,97 @@
+package envstruct
+
+import (
+	"errors"
+	"reflect"
+)
+
+// FetchEnvCopy will deep copy the struct given, fetch the environment
+// variables into the copy and return it. The struct that is passed in is never
+// modified, which allows callers to keep an untouched struct of defaults and
+// compare it against the resolved one. The object needs to be a pointer to a
+// struct and the returned value will be a pointer to a struct of the same type.
+func (e Envstruct) FetchEnvCopy(object interface{}) (interface{}, error) {
+	v := reflect.ValueOf(object)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to parse env into object, needs to be a pointer to a struct")
+	}
+
+	objectCopy := deepCopy(v)
+
+	err := e.FetchEnv(objectCopy.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	return objectCopy.Interface(), nil
+}
+
+// deepCopy returns a copy of the value where any pointers, slices and maps
+// within it are copied rather than shared. Unexported struct fields cannot be
+// set through reflection so they are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+
+	case reflect.Struct:
+		// Start off with a shallow copy so that the unexported fields are copied
+		// over, then replace each exported field with a deep copy of it
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+		}
+		return c
+
+	default:
+		return v
+	}
+}
@@ -0,0 +1,65 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestFetchEnvCopy() {
+	type Nested struct {
+		Field2 []string `tag:"field2"`
+	}
+
+	type Config struct {
+		Field1 string            `tag:"field1"`
+		Nested *Nested           `tag:"nested"`
+		Labels map[string]string `tag:"labels"`
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "prefix",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	os.Setenv("PREFIX_FIELD1", "value")
+	os.Setenv("PREFIX_NESTED_FIELD2", "a,b")
+	os.Setenv("PREFIX_LABELS", "env:prod")
+	defer os.Clearenv()
+
+	defaults := &Config{
+		Field1: "default",
+		Nested: &Nested{Field2: []string{"default"}},
+		Labels: map[string]string{"env": "dev"},
+	}
+
+	s.Run("populates a copy and returns it", func() {
+		resolved, err := env.FetchEnvCopy(defaults)
+		s.NoError(err)
+
+		s.Equal(&Config{
+			Field1: "value",
+			Nested: &Nested{Field2: []string{"a", "b"}},
+			Labels: map[string]string{"env": "prod"},
+		}, resolved)
+	})
+
+	s.Run("leaves the original struct untouched", func() {
+		_, err := env.FetchEnvCopy(defaults)
+		s.NoError(err)
+
+		s.Equal(&Config{
+			Field1: "default",
+			Nested: &Nested{Field2: []string{"default"}},
+			Labels: map[string]string{"env": "dev"},
+		}, defaults)
+	})
+
+	s.Run("errors if the object is not a pointer to a struct", func() {
+		_, err := env.FetchEnvCopy(Config{})
+		s.EqualError(err, "failed to parse env into object, needs to be a pointer to a struct")
+	})
+}
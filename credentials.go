@@ -0,0 +1,72 @@
+package envstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SourceCredentials is the name of the CredentialsDirectory Source
+const SourceCredentials = "credentials"
+
+// CredentialsDirectory is a Source that reads each env from a file of the same
+// name within the directory, which is how systemd passes credentials to
+// services through LoadCredential= and SetCredential= without exposing them
+// in the environment. File names are matched without case and with dashes and
+// dots treated as underscores, so a credential named "db-password" can be
+// fetched as DB_PASSWORD. A single trailing newline is removed from the
+// contents of the file.
+type CredentialsDirectory struct {
+	// Dir is the directory that holds one file per credential. Nothing is
+	// found if it is empty.
+	Dir string
+}
+
+// SystemdCredentials returns a CredentialsDirectory for the directory that
+// systemd sets in $CREDENTIALS_DIRECTORY.
+func SystemdCredentials() CredentialsDirectory {
+	return CredentialsDirectory{Dir: os.Getenv("CREDENTIALS_DIRECTORY")}
+}
+
+// Name returns SourceCredentials
+func (c CredentialsDirectory) Name() string { return SourceCredentials }
+
+// Lookup reads the credential file that matches the env name
+func (c CredentialsDirectory) Lookup(envName string) (string, bool) {
+	if c.Dir == "" {
+		return "", false
+	}
+
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return "", false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || nameNormalizer.Replace(strings.ToUpper(entry.Name())) != envName {
+			continue
+		}
+
+		contents, err := os.ReadFile(filepath.Join(c.Dir, entry.Name()))
+		if err != nil {
+			return "", false
+		}
+
+		value := strings.TrimSuffix(string(contents), "\n")
+		return strings.TrimSuffix(value, "\r"), true
+	}
+
+	return "", false
+}
+
+// Files returns the directory of the credentials
+func (c CredentialsDirectory) Files() []string {
+	if c.Dir == "" {
+		return nil
+	}
+
+	return []string{c.Dir}
+}
+
+// Reload does nothing, as the credentials are read on every lookup
+func (c CredentialsDirectory) Reload() error { return nil }
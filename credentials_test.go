@@ -0,0 +1,51 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestCredentialsDirectory() {
+	type Config struct {
+		Database struct {
+			User     string `tag:"user"`
+			Password string `tag:"password"`
+		} `tag:"db"`
+		Token string `tag:"token"`
+	}
+
+	defer os.Clearenv()
+
+	dir := s.T().TempDir()
+	s.NoError(os.WriteFile(filepath.Join(dir, "db-password"), []byte("hunter2\n"), 0600))
+	s.NoError(os.WriteFile(filepath.Join(dir, "TOKEN"), []byte("abc"), 0600))
+	s.NoError(os.Mkdir(filepath.Join(dir, "DB_USER"), 0700))
+
+	s.Run("reads each env from the credentials directory", func() {
+		os.Setenv("CREDENTIALS_DIRECTORY", dir)
+		os.Setenv("DB_USER", "admin")
+
+		env := envstruct.Envstruct{
+			TagName: "tag",
+			Sources: []envstruct.Source{envstruct.Environment, envstruct.SystemdCredentials()},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("admin", config.Database.User)
+		s.Equal("hunter2", config.Database.Password)
+		s.Equal("abc", config.Token)
+	})
+
+	s.Run("finds nothing when the directory is not set", func() {
+		_, found := envstruct.CredentialsDirectory{}.Lookup("TOKEN")
+		s.False(found)
+	})
+}
@@ -0,0 +1,57 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestDelimOption() {
+	type Config struct {
+		Hosts  []string       `tag:"hosts"`
+		Paths  []string       `tag:"paths,delim=:"`
+		Limits map[string]int `tag:"limits,delim=;"`
+		Matrix [][]int        `tag:"matrix,delim=|"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("splits the field on its own delimiter", func() {
+		os.Clearenv()
+		os.Setenv("APP_HOSTS", "a,b")
+		os.Setenv("APP_PATHS", "/usr/bin:/bin")
+		os.Setenv("APP_LIMITS", "a:1;b:2")
+		os.Setenv("APP_MATRIX", "1|2;3")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Hosts:  []string{"a", "b"},
+			Paths:  []string{"/usr/bin", "/bin"},
+			Limits: map[string]int{"a": 1, "b": 2},
+			Matrix: [][]int{{1, 2}, {3}},
+		}, config)
+	})
+
+	s.Run("joins defaults with the delimiter of the field", func() {
+		os.Clearenv()
+
+		config := Config{Paths: []string{"/usr/bin", "/bin"}}
+		script, err := env.ExportScript(&config, false)
+		s.NoError(err)
+		s.Contains(script, "export APP_PATHS='/usr/bin:/bin'\n")
+	})
+}
@@ -0,0 +1,53 @@
+package envstruct
+
+import (
+	"reflect"
+	"strings"
+)
+
+// defaultDeprecatedTagName is the DeprecatedTagName when it is not set
+const defaultDeprecatedTagName = "deprecated"
+
+// deprecatedNames builds the names of the envs that the field used to be
+// fetched from, as listed within the DeprecatedTagName of the field. Each of
+// them replaces the tag value of the field, so they are prefixed and nested
+// the same way as the names the field is fetched from.
+func (e Envstruct) deprecatedNames(envNameBuilders [][]string, fieldDescription reflect.StructField) []string {
+	tagName := e.DeprecatedTagName
+	if tagName == "" {
+		tagName = defaultDeprecatedTagName
+	}
+
+	value, found := fieldDescription.Tag.Lookup(tagName)
+	if !found {
+		return nil
+	}
+
+	var aliases []string
+	for _, alias := range strings.Split(value, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, e.normalizeName(strings.ToUpper(alias)))
+		}
+	}
+
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	return e.buildNames(appendAliases(envNameBuilders, aliases), fieldDescription)
+}
+
+// warnDeprecated calls the OnDeprecated function (if set) with the deprecated
+// env that the field was fetched from
+func (e Envstruct) warnDeprecated(f *field, envName string) {
+	if e.OnDeprecated == nil {
+		return
+	}
+
+	var newName string
+	if len(f.envNames) > 0 {
+		newName = f.envNames[0]
+	}
+
+	e.OnDeprecated(envName, newName, f.path)
+}
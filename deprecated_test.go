@@ -0,0 +1,110 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestDeprecatedNames() {
+	type Config struct {
+		Database struct {
+			Address string `tag:"address" deprecated:"addr, host"`
+		} `tag:"db"`
+		Port int `tag:"port"`
+	}
+
+	type warning struct {
+		oldName, newName, field string
+	}
+
+	defer os.Clearenv()
+
+	var warnings []warning
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		OnDeprecated: func(oldName, newName, field string) {
+			warnings = append(warnings, warning{oldName, newName, field})
+		},
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("falls back to the deprecated envs and warns about them", func() {
+		os.Clearenv()
+		os.Setenv("APP_DB_HOST", "localhost")
+		warnings = nil
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("localhost", config.Database.Address)
+		s.Equal([]warning{{"APP_DB_HOST", "APP_DB_ADDRESS", "Database.Address"}}, warnings)
+	})
+
+	s.Run("prefers the current env without warning", func() {
+		os.Clearenv()
+		os.Setenv("APP_DB_ADDRESS", "new")
+		os.Setenv("APP_DB_ADDR", "old")
+		warnings = nil
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("new", config.Database.Address)
+		s.Empty(warnings)
+	})
+
+	s.Run("tries the deprecated envs in order", func() {
+		os.Clearenv()
+		os.Setenv("APP_DB_ADDR", "addr")
+		os.Setenv("APP_DB_HOST", "host")
+		warnings = nil
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("addr", config.Database.Address)
+		s.Equal([]warning{{"APP_DB_ADDR", "APP_DB_ADDRESS", "Database.Address"}}, warnings)
+	})
+
+	s.Run("uses the configured tag name", func() {
+		os.Clearenv()
+		os.Setenv("APP_PORT_NUMBER", "8080")
+
+		type Config struct {
+			Port int `tag:"port" old:"port_number"`
+		}
+
+		env := env
+		env.DeprecatedTagName = "old"
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(8080, config.Port)
+	})
+
+	s.Run("fails if a deprecated env is used by another field", func() {
+		os.Clearenv()
+
+		type Config struct {
+			Host    string `tag:"host"`
+			Address string `tag:"address" deprecated:"host"`
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "env APP_HOST is used by both fields Host and Address")
+	})
+}
@@ -0,0 +1,63 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Deriver can be implemented by a struct (or any nested struct) to compute
+// derived fields once the envs have been set, for ex. setting an IsTLS field
+// from a parsed URL. This lets derived fields live next to the config
+// definition rather than in a post processing step after every FetchEnv.
+//
+// Derive is called on nested structs before the structs that contain them, so
+// a struct can rely on the fields of its nested structs having been derived.
+type Deriver interface {
+	Derive() error
+}
+
+// derive will call Derive on the struct and any nested structs that implement
+// Deriver, starting with the most deeply nested structs.
+func derive(v reflect.Value, path []string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		return derive(v.Elem(), path)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanInterface() {
+				continue
+			}
+
+			err := derive(v.Field(i), append(path[:len(path):len(path)], v.Type().Field(i).Name))
+			if err != nil {
+				return err
+			}
+		}
+
+		if !v.CanAddr() {
+			return nil
+		}
+
+		deriver, ok := v.Addr().Interface().(Deriver)
+		if !ok {
+			return nil
+		}
+
+		err := deriver.Derive()
+		if err != nil {
+			if len(path) == 0 {
+				return fmt.Errorf("failed to derive fields: %w", err)
+			}
+
+			return fmt.Errorf("failed to derive fields of %s: %w", strings.Join(path, "."), err)
+		}
+	}
+
+	return nil
+}
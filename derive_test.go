@@ -0,0 +1,87 @@
+package envstruct_test
+
+import (
+	"errors"
+	"net/url"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type DerivedDatabase struct {
+	URL   string `tag:"url"`
+	IsTLS bool
+}
+
+func (d *DerivedDatabase) Derive() error {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return err
+	}
+
+	d.IsTLS = u.Scheme == "https"
+	return nil
+}
+
+type DerivedConfig struct {
+	Database DerivedDatabase `tag:"db"`
+	Summary  string
+}
+
+func (c *DerivedConfig) Derive() error {
+	if c.Database.URL == "" {
+		return errors.New("no database configured")
+	}
+
+	// The nested struct has already been derived
+	c.Summary = "tls"
+	if !c.Database.IsTLS {
+		c.Summary = "plaintext"
+	}
+
+	return nil
+}
+
+func (s *EnvstructSuite) TestDerive() {
+	env := envstruct.Envstruct{
+		Prefix:  "prefix",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	defer os.Clearenv()
+
+	s.Run("derives fields after the envs are set, nested structs first", func() {
+		os.Setenv("PREFIX_DB_URL", "https://db.example.com")
+
+		var config DerivedConfig
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(DerivedConfig{
+			Database: DerivedDatabase{
+				URL:   "https://db.example.com",
+				IsTLS: true,
+			},
+			Summary: "tls",
+		}, config)
+	})
+
+	s.Run("returns errors from deriving fields", func() {
+		os.Clearenv()
+
+		var config DerivedConfig
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "failed to derive fields: no database configured")
+	})
+
+	s.Run("includes the path of nested structs that fail to derive", func() {
+		os.Setenv("PREFIX_DB_URL", "://bad")
+
+		var config DerivedConfig
+		err := env.FetchEnv(&config)
+		s.EqualError(err, `failed to derive fields of Database: parse "://bad": missing protocol scheme`)
+	})
+}
@@ -0,0 +1,60 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// dockerfileQuoter escapes the characters that are not written as they are
+// within double quotes in a Dockerfile, where "$" would otherwise reference
+// another variable
+var dockerfileQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+
+// DockerfileEnv renders the defaults of the struct as `ENV NAME=value` lines
+// of a Dockerfile, in the order that the fields are declared, so that images
+// can be built with the same defaults as the struct. The default from the
+// DefaultTagName is used, or otherwise the value already set on the field,
+// and fields without either are left out. Secrets are always left out, as
+// the ENV instructions of an image can be read by anyone who can pull it.
+func (e Envstruct) DockerfileEnv(object interface{}) (string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return "", errors.New("failed to render dockerfile env for object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return "", err
+	}
+
+	var lines strings.Builder
+	for _, f := range fields {
+		if !f.tagged || len(f.envNames) == 0 || f.options.secret {
+			continue
+		}
+
+		spec := e.fieldSpec(f)
+		if spec.Default == "" {
+			continue
+		}
+
+		if strings.ContainsAny(spec.Default, "\r\n") {
+			return "", fmt.Errorf("default of %s contains a line break, which cannot be written within a Dockerfile", f.envNames[0])
+		}
+
+		fmt.Fprintf(&lines, "ENV %s=%s\n", f.envNames[0], dockerfileQuote(spec.Default))
+	}
+
+	return lines.String(), nil
+}
+
+// dockerfileQuote wraps the value in double quotes unless it only contains
+// characters that are never interpreted within a Dockerfile
+func dockerfileQuote(value string) string {
+	if isPlainValue(value) {
+		return value
+	}
+
+	return `"` + dockerfileQuoter.Replace(value) + `"`
+}
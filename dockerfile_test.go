@@ -0,0 +1,48 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestDockerfileEnv() {
+	type Config struct {
+		Port     int           `tag:"port"`
+		Timeout  time.Duration `tag:"timeout" default:"30s"`
+		Greeting string        `tag:"greeting"`
+		Template string        `tag:"template"`
+		Password string        `tag:"password,secret" default:"hunter2"`
+		Debug    bool          `tag:"debug"`
+		Excluded string        `tag:"-"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:         "app",
+		TagName:        "tag",
+		DefaultTagName: "default",
+	}
+
+	s.Run("renders an ENV line for each default", func() {
+		dockerfile, err := env.DockerfileEnv(&Config{
+			Port:     8080,
+			Greeting: `say "hi"`,
+			Template: "$HOME/templates",
+			Excluded: "excluded",
+			Internal: "internal",
+		})
+		s.NoError(err)
+
+		s.Equal(`ENV APP_PORT=8080
+ENV APP_TIMEOUT=30s
+ENV APP_GREETING="say \"hi\""
+ENV APP_TEMPLATE="\$HOME/templates"
+`, dockerfile)
+	})
+
+	s.Run("fails on defaults with line breaks", func() {
+		_, err := env.DockerfileEnv(&Config{Greeting: "hello\nworld"})
+		s.EqualError(err, "default of APP_GREETING contains a line break, which cannot be written within a Dockerfile")
+	})
+}
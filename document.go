@@ -0,0 +1,72 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SourceDocument is the name of the Document Source
+const SourceDocument = "document"
+
+// Document is a Source for a JSON or YAML document that is read once from a
+// reader, for ex. stdin so that a pipeline can pass in the configuration with
+// `--config -` without writing it to a temporary file or to the environment.
+// The document is flattened the same way as a JSONFile.
+type Document struct {
+	values map[string]string
+}
+
+// ReadDocument reads the whole reader and unmarshals it with the unmarshal
+// func, for ex. yaml.Unmarshal. If it is nil, the document is read as JSON.
+// The document needs to contain an object.
+func ReadDocument(r io.Reader, unmarshal UnmarshalFunc) (*Document, error) {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config document: %w", err)
+	}
+
+	var parsed map[string]interface{}
+	err = unmarshal(contents, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config document: %w", err)
+	}
+
+	values := map[string]string{}
+	flatten(values, "", parsed)
+
+	return &Document{values: values}, nil
+}
+
+// OpenDocument reads the document at the path, or from stdin if the path is
+// "-", which is the usual convention for a `--config` flag.
+func OpenDocument(path string, unmarshal UnmarshalFunc) (*Document, error) {
+	if path == "-" {
+		return ReadDocument(os.Stdin, unmarshal)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config document: %w", err)
+	}
+	defer file.Close()
+
+	return ReadDocument(file, unmarshal)
+}
+
+// Name returns SourceDocument
+func (d *Document) Name() string { return SourceDocument }
+
+// Lookup returns the value of the env within the document
+func (d *Document) Lookup(envName string) (string, bool) {
+	value, found := d.values[envName]
+	return value, found
+}
+
+// Names returns the name of every env within the document
+func (d *Document) Names() []string { return mapNames(d.values) }
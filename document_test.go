@@ -0,0 +1,92 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestDocument() {
+	type Config struct {
+		Database struct {
+			Host     string   `tag:"host"`
+			Port     int      `tag:"port"`
+			Replicas []string `tag:"replicas"`
+		} `tag:"db"`
+		Debug bool `tag:"debug"`
+	}
+
+	defer os.Clearenv()
+
+	fetch := func(document *envstruct.Document) Config {
+		env := envstruct.Envstruct{
+			TagName: "tag",
+			Sources: []envstruct.Source{envstruct.Environment, document},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		return config
+	}
+
+	s.Run("reads a JSON document", func() {
+		os.Setenv("DB_PORT", "6543")
+
+		document, err := envstruct.ReadDocument(strings.NewReader(`{
+  "db": {"host": "localhost", "port": 5432, "replicas": ["a", "b"]},
+  "debug": true
+}`), nil)
+		s.NoError(err)
+		s.Equal(envstruct.SourceDocument, document.Name())
+
+		config := fetch(document)
+		s.Equal("localhost", config.Database.Host)
+		s.Equal(6543, config.Database.Port)
+		s.Equal([]string{"a", "b"}, config.Database.Replicas)
+		s.True(config.Debug)
+	})
+
+	s.Run("reads a YAML document", func() {
+		os.Clearenv()
+
+		document, err := envstruct.ReadDocument(strings.NewReader(`
+db:
+  host: localhost
+  port: 5432
+  replicas: [a, b]
+debug: true
+`), yaml.Unmarshal)
+		s.NoError(err)
+
+		config := fetch(document)
+		s.Equal("localhost", config.Database.Host)
+		s.Equal(5432, config.Database.Port)
+		s.Equal([]string{"a", "b"}, config.Database.Replicas)
+		s.True(config.Debug)
+	})
+
+	s.Run("opens a document at a path", func() {
+		path := filepath.Join(s.T().TempDir(), "config.json")
+		s.NoError(os.WriteFile(path, []byte(`{"db": {"host": "localhost"}}`), 0600))
+
+		document, err := envstruct.OpenDocument(path, nil)
+		s.NoError(err)
+
+		value, found := document.Lookup("DB_HOST")
+		s.True(found)
+		s.Equal("localhost", value)
+	})
+
+	s.Run("fails on a document that is not an object", func() {
+		_, err := envstruct.ReadDocument(strings.NewReader(`["a"]`), nil)
+		s.Error(err)
+		s.Contains(err.Error(), "failed to parse config document")
+	})
+}
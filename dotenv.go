@@ -0,0 +1,223 @@
+package envstruct
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SourceDotenvFile is the name of the DotenvFile Source
+const SourceDotenvFile = "dotenv_file"
+
+// DotenvFile is a Source for a .env file, which holds one KEY=value per line.
+// Lines starting with "#" are comments and can also follow unquoted values,
+// an "export " in front of the key is ignored and values can be wrapped in
+// single or double quotes. Escapes such as \n are only replaced within double
+// quotes.
+//
+// Values can be encrypted with EncryptDotenvValue so that the file can be
+// committed safely. The encryption is specific to envstruct and is not
+// compatible with other tools such as dotenvx. The values are decrypted when
+// the file is read, which needs the private key in one of:
+//
+//   - the ENVSTRUCT_PRIVATE_KEY env
+//   - the file at the path in the ENVSTRUCT_PRIVATE_KEY_FILE env
+//   - the ENVSTRUCT_PRIVATE_KEY within a .envstruct.keys file next to the .env
+//     file
+type DotenvFile struct {
+	// Path is the path of the file that was read
+	Path string
+
+	*fileValues
+}
+
+// ReadDotenvFile reads the .env file at the path, decrypting any encrypted
+// values
+func ReadDotenvFile(path string) (*DotenvFile, error) {
+	file := &DotenvFile{Path: path, fileValues: newFileValues(path, readDotenvFile)}
+
+	err := file.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// ReadOptionalDotenvFile is the same as ReadDotenvFile, except that a file
+// that does not exist is treated as empty rather than failing. This allows the
+// same Sources to be used for local development, where the .env file exists,
+// and production, where it does not. The file is still watched, so that it is
+// picked up if it is created later on.
+func ReadOptionalDotenvFile(path string) (*DotenvFile, error) {
+	file := &DotenvFile{Path: path, fileValues: newFileValues(path, readOptionalDotenvFile)}
+
+	err := file.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func readOptionalDotenvFile(path string) (map[string]string, error) {
+	values, err := readDotenvFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+
+	return values, err
+}
+
+func readDotenvFile(path string) (map[string]string, error) {
+	values, err := parseDotenvFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var privateKey string
+	for name, value := range values {
+		if !isEncryptedDotenvValue(value) {
+			continue
+		}
+
+		if privateKey == "" {
+			privateKey, err = dotenvPrivateKey(path)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		values[name], err = decryptDotenvValue(privateKey, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s in %s: %w", name, path, err)
+		}
+	}
+
+	return values, nil
+}
+
+// dotenvPrivateKey finds the private key used to decrypt the values of the
+// .env file
+func dotenvPrivateKey(path string) (string, error) {
+	if privateKey := os.Getenv("ENVSTRUCT_PRIVATE_KEY"); privateKey != "" {
+		return privateKey, nil
+	}
+
+	if keyFile := os.Getenv("ENVSTRUCT_PRIVATE_KEY_FILE"); keyFile != "" {
+		contents, err := os.ReadFile(keyFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read private key: %w", err)
+		}
+
+		return strings.TrimSpace(string(contents)), nil
+	}
+
+	keys, err := parseDotenvFile(filepath.Join(filepath.Dir(path), ".envstruct.keys"))
+	if err == nil && keys["ENVSTRUCT_PRIVATE_KEY"] != "" {
+		return keys["ENVSTRUCT_PRIVATE_KEY"], nil
+	}
+
+	return "", fmt.Errorf("%s contains encrypted values but no private key was found, set ENVSTRUCT_PRIVATE_KEY or ENVSTRUCT_PRIVATE_KEY_FILE", path)
+}
+
+// parseDotenvFile parses each KEY=value line of the file
+func parseDotenvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dotenv file: %w", err)
+	}
+	defer file.Close()
+
+	return parseDotenv(file, path)
+}
+
+// parseDotenv parses each KEY=value line read from the reader. The path is
+// used to give context to errors.
+func parseDotenv(r io.Reader, path string) (map[string]string, error) {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		keyVal := strings.SplitN(line, "=", 2)
+		if len(keyVal) != 2 {
+			return nil, fmt.Errorf("failed to parse dotenv file %s on line %d: expected KEY=value", path, lineNumber)
+		}
+
+		value, err := parseDotenvValue(strings.TrimSpace(keyVal[1]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dotenv file %s on line %d: %w", path, lineNumber, err)
+		}
+
+		values[strings.TrimSpace(keyVal[0])] = value
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dotenv file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// dotenvEscapes are the escapes that are replaced within double quotes
+var dotenvEscapes = strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`)
+
+// parseDotenvValue removes the quotes around the value, or the comment after
+// it if it is not quoted
+func parseDotenvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch quote := value[0]; quote {
+	case '"', '\'':
+		end := -1
+		for i := 1; i < len(value); i++ {
+			if value[i] == '\\' && quote == '"' {
+				i++
+				continue
+			}
+
+			if value[i] == quote {
+				end = i
+				break
+			}
+		}
+
+		if end == -1 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+
+		if quote == '"' {
+			return dotenvEscapes.Replace(value[1:end]), nil
+		}
+
+		return value[1:end], nil
+	}
+
+	if i := strings.Index(value, " #"); i != -1 {
+		value = value[:i]
+	}
+
+	return strings.TrimSpace(value), nil
+}
+
+// Name returns SourceDotenvFile
+func (d *DotenvFile) Name() string { return SourceDotenvFile }
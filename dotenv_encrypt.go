@@ -0,0 +1,134 @@
+package envstruct
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix is put in front of encrypted values within .env files. It
+// names envstruct so that the values are not mistaken for the ones of other
+// tools, as the encryption is not compatible with them.
+const encryptedPrefix = "envstruct:encrypted:"
+
+// GenerateDotenvKeys generates a new key pair for encrypting the values of
+// .env files, encoded as hex. The public key can be committed alongside the
+// .env file while the private key needs to be kept secret.
+func GenerateDotenvKeys() (string, string, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(privateKey.PublicKey().Bytes()), hex.EncodeToString(privateKey.Bytes()), nil
+}
+
+// EncryptDotenvValue encrypts the value for the public key, returning a value
+// that can be written to a .env file and is decrypted by ReadDotenvFile. Each
+// value is encrypted with a new ephemeral X25519 key and AES-256-GCM.
+func EncryptDotenvValue(publicKey string, value string) (string, error) {
+	publicKeyBytes, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+
+	recipient, err := ecdh.X25519().NewPublicKey(publicKeyBytes)
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := dotenvCipher(ephemeral, recipient, ephemeral.PublicKey())
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := append(ephemeral.PublicKey().Bytes(), nonce...)
+	sealed = aead.Seal(sealed, nonce, []byte(value), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func isEncryptedDotenvValue(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// decryptDotenvValue decrypts a value that was encrypted by EncryptDotenvValue
+func decryptDotenvValue(privateKey string, value string) (string, error) {
+	privateKeyBytes, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid private key: %w", err)
+	}
+
+	recipient, err := ecdh.X25519().NewPrivateKey(privateKeyBytes)
+	if err != nil {
+		return "", fmt.Errorf("invalid private key: %w", err)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+
+	keySize := len(recipient.PublicKey().Bytes())
+	if len(sealed) < keySize {
+		return "", errors.New("invalid encrypted value: too short")
+	}
+
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed[:keySize])
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+
+	aead, err := dotenvCipher(recipient, ephemeral, ephemeral)
+	if err != nil {
+		return "", err
+	}
+
+	sealed = sealed[keySize:]
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("invalid encrypted value: too short")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("the value was not encrypted for this private key")
+	}
+
+	return string(plaintext), nil
+}
+
+// dotenvCipher derives the AES-256-GCM cipher from the shared secret between
+// the private and public key, bound to the ephemeral public key of the value
+func dotenvCipher(privateKey *ecdh.PrivateKey, publicKey *ecdh.PublicKey, ephemeral *ecdh.PublicKey) (cipher.AEAD, error) {
+	shared, err := privateKey.ECDH(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key := sha256.Sum256(append(shared, ephemeral.Bytes()...))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
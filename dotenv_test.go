@@ -0,0 +1,172 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestDotenvFile() {
+	type Config struct {
+		Host     string   `tag:"host"`
+		Port     int      `tag:"port"`
+		Hosts    []string `tag:"hosts"`
+		Literal  string   `tag:"literal"`
+		Password string   `tag:"password"`
+	}
+
+	defer os.Clearenv()
+
+	publicKey, privateKey, err := envstruct.GenerateDotenvKeys()
+	s.NoError(err)
+
+	encrypted, err := envstruct.EncryptDotenvValue(publicKey, "hunter2")
+	s.NoError(err)
+
+	writeDotenv := func(dir string) string {
+		path := filepath.Join(dir, ".env")
+		s.NoError(os.WriteFile(path, []byte(`# Local settings
+HOST=localhost # the host
+export PORT=8080
+HOSTS="a,b"
+MESSAGE="hello\nworld"
+LITERAL='hello\nworld'
+PASSWORD=`+encrypted+`
+`), 0600))
+
+		return path
+	}
+
+	env := envstruct.Envstruct{
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("decrypts values with the private key from the env", func() {
+		os.Setenv("ENVSTRUCT_PRIVATE_KEY", privateKey)
+
+		file, err := envstruct.ReadDotenvFile(writeDotenv(s.T().TempDir()))
+		s.NoError(err)
+
+		env.Sources = []envstruct.Source{file}
+
+		var config Config
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Host:     "localhost",
+			Port:     8080,
+			Hosts:    []string{"a", "b"},
+			Literal:  `hello\nworld`,
+			Password: "hunter2",
+		}, config)
+
+		message, found := file.Lookup("MESSAGE")
+		s.True(found)
+		s.Equal("hello\nworld", message)
+	})
+
+	s.Run("decrypts values with the private key from a .envstruct.keys file", func() {
+		os.Clearenv()
+
+		dir := s.T().TempDir()
+		s.NoError(os.WriteFile(filepath.Join(dir, ".envstruct.keys"), []byte("ENVSTRUCT_PRIVATE_KEY="+privateKey+"\n"), 0600))
+
+		file, err := envstruct.ReadDotenvFile(writeDotenv(dir))
+		s.NoError(err)
+
+		value, found := file.Lookup("PASSWORD")
+		s.True(found)
+		s.Equal("hunter2", value)
+	})
+
+	s.Run("fails without the private key", func() {
+		os.Clearenv()
+
+		path := writeDotenv(s.T().TempDir())
+
+		_, err := envstruct.ReadDotenvFile(path)
+		s.EqualError(err, path+" contains encrypted values but no private key was found, set ENVSTRUCT_PRIVATE_KEY or ENVSTRUCT_PRIVATE_KEY_FILE")
+	})
+
+	s.Run("fails with the wrong private key", func() {
+		_, otherKey, err := envstruct.GenerateDotenvKeys()
+		s.NoError(err)
+
+		os.Setenv("ENVSTRUCT_PRIVATE_KEY", otherKey)
+
+		path := writeDotenv(s.T().TempDir())
+
+		_, err = envstruct.ReadDotenvFile(path)
+		s.EqualError(err, "failed to decrypt PASSWORD in "+path+": the value was not encrypted for this private key")
+	})
+}
+
+func (s *EnvstructSuite) TestOptionalDotenvFile() {
+	type Config struct {
+		Host string `tag:"host"`
+		Port int    `tag:"port"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("is empty when the file does not exist", func() {
+		path := filepath.Join(s.T().TempDir(), ".env")
+
+		file, err := envstruct.ReadOptionalDotenvFile(path)
+		s.NoError(err)
+		s.Equal([]string{path}, file.Files())
+
+		os.Setenv("APP_HOST", "localhost")
+
+		env.Sources = []envstruct.Source{envstruct.Environment, file}
+
+		var config Config
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal(Config{Host: "localhost"}, config)
+
+		s.NoError(os.WriteFile(path, []byte("APP_PORT=8080\n"), 0600))
+		s.NoError(file.Reload())
+
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal(Config{Host: "localhost", Port: 8080}, config)
+	})
+
+	s.Run("lets the file win over the environment when it is first", func() {
+		path := filepath.Join(s.T().TempDir(), ".env")
+		s.NoError(os.WriteFile(path, []byte("APP_HOST=from-file\n"), 0600))
+
+		file, err := envstruct.ReadOptionalDotenvFile(path)
+		s.NoError(err)
+
+		os.Setenv("APP_HOST", "from-env")
+
+		env.Sources = []envstruct.Source{file, envstruct.Environment}
+
+		var config Config
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal("from-file", config.Host)
+	})
+
+	s.Run("fails when the file can not be parsed", func() {
+		path := filepath.Join(s.T().TempDir(), ".env")
+		s.NoError(os.WriteFile(path, []byte("APP_HOST\n"), 0600))
+
+		_, err := envstruct.ReadOptionalDotenvFile(path)
+		s.Error(err)
+	})
+}
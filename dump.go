@@ -0,0 +1,156 @@
+package envstruct
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// redacted replaces the values of secret fields when the configuration is
+// displayed
+const redacted = "******"
+
+// Dump returns the current configuration of the struct as a map of env names
+// to the values of the fields. Only fields with a tag matching the TagName are
+// included, and the values of fields with the "secret" tag option are
+// redacted. If a field can be fetched from multiple envs, the env with the
+// highest precedence is used.
+func (e Envstruct) Dump(object interface{}) (map[string]interface{}, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to dump object, needs to be type struct")
+	}
+
+	return e.dump(object, true)
+}
+
+// dump returns the configuration of the struct as a map of env names to the
+// values of the fields, optionally redacting secrets.
+func (e Envstruct) dump(object interface{}, redact bool) (map[string]interface{}, error) {
+	fields, err := e.fields(object)
+	if err != nil {
+		return nil, err
+	}
+
+	dump := map[string]interface{}{}
+	for _, f := range fields {
+		// Fields that have no env, for ex. because the NameFunc returned no
+		// names for them, can not be dumped
+		if !f.tagged || len(f.envNames) == 0 {
+			continue
+		}
+
+		dump[f.envNames[0]] = dumpValue(f.value, redact && f.options.secret)
+	}
+
+	return dump, nil
+}
+
+// Fingerprint returns a stable hash of the current configuration of the
+// struct, as returned by Dump, so that instances running with different
+// configurations can be told apart at a glance, for ex. within log lines. As
+// secrets are redacted, only whether a secret is set changes the fingerprint.
+func (e Envstruct) Fingerprint(object interface{}) (string, error) {
+	dump, err := e.Dump(object)
+	if err != nil {
+		return "", err
+	}
+
+	return fingerprint(dump)
+}
+
+// fingerprint hashes the dump. Maps are marshalled with their keys sorted, so
+// the same configuration always gives the same fingerprint.
+func fingerprint(dump map[string]interface{}) (string, error) {
+	marshalled, err := json.Marshal(dump)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(marshalled)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// dumpValue returns the value of the field in a form that can be displayed.
+// Secrets are redacted, but an unset secret is shown as empty so that it is
+// still possible to tell whether it has been set.
+func dumpValue(v reflect.Value, secret bool) interface{} {
+	if secret {
+		if v.IsZero() {
+			return ""
+		}
+
+		return redacted
+	}
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	// Optional values are shown as their value, or nil if they were not set
+	if optional, ok := v.Interface().(optionalGetter); ok {
+		value, present := optional.optionalValue()
+		if !present {
+			return nil
+		}
+
+		return dumpValue(reflect.ValueOf(value), false)
+	}
+
+	// Types such as time.Duration are more readable as strings than in the form
+	// they would be marshalled in
+	if stringer, ok := v.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return v.Interface()
+}
+
+// Handler returns an http.Handler that renders the current configuration of
+// the struct as JSON, with the values of secret fields redacted. The
+// Fingerprint of the configuration is set as the ETag of the response. It is
+// meant to be mounted on an internal debug port. The struct is read on every
+// request, so it must not be modified while the handler is serving requests.
+func (e Envstruct) Handler(object interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		dump, err := e.Dump(object)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		fingerprint, err := fingerprint(dump)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("ETag", `"`+fingerprint+`"`)
+		writeJSON(w, dump)
+	})
+}
+
+// writeJSON writes the value as an indented JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
@@ -0,0 +1,98 @@
+package envstruct_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+type DumpConfig struct {
+	Host     string        `tag:"host"`
+	Timeout  time.Duration `tag:"timeout"`
+	Password string        `tag:"password,secret"`
+	APIKey   string        `tag:"api_key,secret"`
+	Internal string
+	Database struct {
+		Hosts []string `tag:"hosts"`
+		Port  *int     `tag:"port"`
+	} `tag:"db"`
+}
+
+func (s *EnvstructSuite) TestDump() {
+	env := envstruct.Envstruct{
+		Prefix:  "prefix",
+		TagName: "tag",
+	}
+
+	config := DumpConfig{
+		Host:     "localhost",
+		Timeout:  time.Minute,
+		Password: "hunter2",
+	}
+	config.Database.Hosts = []string{"a", "b"}
+
+	s.Run("dumps tagged fields by env name with secrets redacted", func() {
+		dump, err := env.Dump(&config)
+		s.NoError(err)
+
+		s.Equal(map[string]interface{}{
+			"PREFIX_HOST":     "localhost",
+			"PREFIX_TIMEOUT":  "1m0s",
+			"PREFIX_PASSWORD": "******",
+			"PREFIX_API_KEY":  "",
+			"PREFIX_DB_HOSTS": []string{"a", "b"},
+			"PREFIX_DB_PORT":  nil,
+		}, dump)
+	})
+
+	s.Run("serves the dump as json", func() {
+		recorder := httptest.NewRecorder()
+		env.Handler(&config).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config", nil))
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Equal("application/json", recorder.Header().Get("Content-Type"))
+		s.JSONEq(`{
+			"PREFIX_API_KEY": "",
+			"PREFIX_DB_HOSTS": ["a", "b"],
+			"PREFIX_DB_PORT": null,
+			"PREFIX_HOST": "localhost",
+			"PREFIX_PASSWORD": "******",
+			"PREFIX_TIMEOUT": "1m0s"
+		}`, recorder.Body.String())
+	})
+
+	s.Run("fingerprints the configuration", func() {
+		fingerprint, err := env.Fingerprint(&config)
+		s.NoError(err)
+		s.Len(fingerprint, 64)
+
+		same, err := env.Fingerprint(&config)
+		s.NoError(err)
+		s.Equal(fingerprint, same)
+
+		rotated := config
+		rotated.Password = "hunter3"
+		same, err = env.Fingerprint(&rotated)
+		s.NoError(err)
+		s.Equal(fingerprint, same)
+
+		changed := config
+		changed.Host = "example.com"
+		different, err := env.Fingerprint(&changed)
+		s.NoError(err)
+		s.NotEqual(fingerprint, different)
+
+		recorder := httptest.NewRecorder()
+		env.Handler(&config).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config", nil))
+		s.Equal(`"`+fingerprint+`"`, recorder.Header().Get("ETag"))
+	})
+
+	s.Run("only allows reading the config", func() {
+		recorder := httptest.NewRecorder()
+		env.Handler(&config).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/config", nil))
+
+		s.Equal(http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
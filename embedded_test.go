@@ -0,0 +1,90 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type Base struct {
+	Host string `tag:"host" yaml:"host"`
+	Port int    `tag:"port" yaml:"port"`
+}
+
+type Logging struct {
+	Level string `tag:"level"`
+}
+
+func (s *EnvstructSuite) TestEmbeddedStructs() {
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("treats the fields of untagged embedded structs as fields of the parent", func() {
+		os.Clearenv()
+		os.Setenv("APP_HOST", "localhost")
+		os.Setenv("APP_PORT", "8080")
+		os.Setenv("APP_LOG_LEVEL", "debug")
+
+		type Config struct {
+			Base
+			*Logging `tag:"log"`
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Base:    Base{Host: "localhost", Port: 8080},
+			Logging: &Logging{Level: "debug"},
+		}, config)
+	})
+
+	s.Run("inlines structs with the inline or squash options", func() {
+		os.Clearenv()
+		os.Setenv("APP_HOST", "localhost")
+		os.Setenv("APP_LEVEL", "debug")
+
+		type Config struct {
+			Server  Base    `tag:",inline"`
+			Logging Logging `tag:"logging,squash"`
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Server:  Base{Host: "localhost"},
+			Logging: Logging{Level: "debug"},
+		}, config)
+	})
+
+	s.Run("reuses inline yaml tags", func() {
+		os.Clearenv()
+		os.Setenv("APP_HOST", "localhost")
+
+		type Config struct {
+			Base `yaml:",inline"`
+		}
+
+		env := env
+		env.TagName = "yaml"
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Base: Base{Host: "localhost"}}, config)
+	})
+}
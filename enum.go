@@ -0,0 +1,44 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldEnum returns the only values that the field can be set to, as listed
+// within the EnumTagName of the field
+func (e Envstruct) fieldEnum(tag reflect.StructTag) []string {
+	if e.EnumTagName == "" {
+		return nil
+	}
+
+	value, found := tag.Lookup(e.EnumTagName)
+	if !found {
+		return nil
+	}
+
+	var enum []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			enum = append(enum, v)
+		}
+	}
+
+	return enum
+}
+
+// checkEnum returns an error if the raw value of the assignment is not one of
+// the values that its field is restricted to. The value is left out of the
+// error for secrets.
+func (a *assignment) checkEnum() error {
+	if len(a.enum) == 0 || containsString(a.enum, strings.TrimSpace(a.raw)) {
+		return nil
+	}
+
+	if a.options.secret {
+		return fmt.Errorf("value is not one of %s", strings.Join(a.enum, ", "))
+	}
+
+	return fmt.Errorf("value %s is not one of %s", a.raw, strings.Join(a.enum, ", "))
+}
@@ -3,12 +3,18 @@ package envstruct
 import (
 	"errors"
 	"fmt"
-	"os"
 	"reflect"
-	"strconv"
+	"sort"
 	"strings"
+	"time"
 )
 
+// Envstruct holds the configuration for fetching environment variables into
+// structs. It is never modified by FetchEnv, so a single Envstruct can be
+// shared and used to call FetchEnv concurrently from multiple goroutines as
+// long as each call is given a distinct struct to populate. Any functions that are set
+// on it (such as the Unmarshaler or OnConflict) need to be safe to call
+// concurrently for this to hold.
 type Envstruct struct {
 	// Prefix is optional and if set, is used as the prefix to any environment
 	// variable fetching. For example, if we are fetching env `FIELD1` and we
@@ -16,9 +22,23 @@ type Envstruct struct {
 	// environment variable.
 	Prefix string
 
+	// Prefixes is optional and if set, each of the prefixes are tried in order
+	// after the Prefix (or the prefix that the struct declares for itself),
+	// with the first prefix that has the env of a field set being used. This
+	// allows a renamed service to keep reading the envs of its old name during
+	// a migration, for ex. []string{"NEWAPP", "OLDAPP"}.
+	Prefixes []string
+
 	// TagName is used for fetching the tag value from the field.
 	TagName string
 
+	// TagNames is optional and if set, each of the tag names are tried in order
+	// (after the TagName, if it is set) and the first tag that is found on the
+	// field is used. This allows structs that are already tagged for
+	// unmarshalling files, for ex. with yaml or json tags, to be reused without
+	// duplicating the tags on every field.
+	TagNames []string
+
 	// Override is optional and if set, it will be used as the tag name that . This
 	// override string will be used directly without any modifications such as
 	// upper casing, appending nested tag values or adding the prefix. You can
@@ -50,132 +70,610 @@ type Envstruct struct {
 	// slices and maps but the underlying values within those types are parsed by
 	// the unmarshaler.
 	Parser Parser
+
+	// BestEffort is default to false. When it is on, FetchEnv will not stop at
+	// the first field that fails to be fetched. Instead it will set every field
+	// that it can and return an Errors value containing each of the failures.
+	// This is for tools that would rather start up in a degraded state with
+	// warnings than refuse to start.
+	BestEffort bool
+
+	// RequireAll is default to false. When it is on, every field that has a tag
+	// matching the TagName is treated as required and FetchEnv will return an
+	// error if none of the envs for the field are set. A field can opt out by
+	// adding the "optional" option to its tag, for ex. `env:"field,optional"`.
+	// Without RequireAll, single fields can be required by adding the
+	// "required" option to their tag, for ex. `env:"field,required"`.
+	RequireAll bool
+
+	// DeriveNames is default to false. When it is on, fields without a tag
+	// matching the TagName are fetched as if they were tagged with the name of
+	// the field converted into SNAKE_CASE, for ex. "MaxIdleConns" is fetched
+	// from "MAX_IDLE_CONNS", so that small structs do not need tags at all.
+	// Embedded structs are still inlined into their parent.
+	DeriveNames bool
+
+	// NameSeparator is optional and if set, it is used to join the segments of
+	// the names of the envs, which are the prefix followed by the tag values of
+	// each nested struct and the field. It is defaulted to an underscore "_".
+	// Setting it to a double underscore "__" separates nesting levels from the
+	// words within a tag value, for ex. "PREFIX__DB__MAX_CONNS".
+	NameSeparator string
+
+	// NameFunc is optional and if set, it builds the names of the envs of each
+	// field instead of BuildName, so that other naming conventions can be
+	// used, for ex. double underscores between nested structs or legacy names
+	// that are still accepted. It is passed the segments that the name is
+	// built from, which are the prefix followed by the tag values of each
+	// nested struct and the field, along with the field itself. The names are
+	// tried in the order that they are returned. It is called once for each
+	// alias of the field, and the OverrideName still takes precedence.
+	NameFunc func(path []string, field reflect.StructField) []string
+
+	// RejectUnexported is default to false. Unexported fields cannot be set, so
+	// they are skipped. When it is on, an unexported field that has a tag
+	// matching the TagName fails FetchEnv instead, as the tag is most likely a
+	// mistake.
+	RejectUnexported bool
+
+	// RejectUnknown is default to false. When it is on, FetchEnv fails if any
+	// env that starts with the prefix is set but none of the fields are
+	// fetched from it, so that a typo such as PREFIX_TIMEOUTT is not silently
+	// ignored. Only the sources that implement ListableSource are checked, and
+	// nothing is checked when there is no prefix. The envs of fields that are
+	// not resolved in the active profile are reported as well.
+	RejectUnknown bool
+
+	// DeprecatedTagName is optional and if set, it will be used as the tag name
+	// that lists the names the field used to be fetched from, separated by a
+	// comma, for ex. `env:"address" deprecated:"addr"`. They are prefixed and
+	// nested the same way as the tag value, and are only tried once none of
+	// the envs of the field are set. It is defaulted to "deprecated".
+	DeprecatedTagName string
+
+	// OnDeprecated is optional and if set, it is called whenever a field is
+	// fetched from one of its deprecated envs. It is passed the name of the
+	// deprecated env, the name of the env that replaces it and the path of the
+	// field (for ex. "Nested.Field"), so that a warning can be logged while
+	// the old name keeps working.
+	OnDeprecated func(oldName, newName, field string)
+
+	// OnConflict is optional and if set, it is called whenever an env would
+	// overwrite a field that already has a non-zero value set on the struct. It
+	// is passed the path of the field (for ex. "Nested.Field") and the name of
+	// the env. If it returns an error then FetchEnv will fail with that error,
+	// otherwise the env will overwrite the value. This is to catch fields that
+	// are configured both in code and in the environment, either as an error by
+	// using ConflictError or as a warning by logging and returning nil.
+	OnConflict func(field string, envName string) error
+
+	// Overrides is optional and if set, pins fields to the given values
+	// regardless of the environment. It is a map of the path to the field (the
+	// names of the fields separated by dots, for ex. "Nested.Field") to the
+	// value, which is parsed in the same way as an env value would be. This is
+	// useful for tests and feature flag systems that want to pin specific
+	// fields without touching the environment.
+	Overrides map[string]string
+
+	// Interpolate is default to false. When it is on, values can reference other
+	// fields by their path, for ex. "{{.Host}}:{{.Port}}", which will be
+	// replaced with the values of those fields once every env has been fetched.
+	// If the referenced field was not fetched, the value already set on the
+	// struct is used.
+	Interpolate bool
+
+	// NormalizeNames is default to false. When it is on, any dashes "-" or dots
+	// "." in the prefix, tag values and override names are replaced with
+	// underscores "_" when building the names of the envs. Tags that are reused
+	// from yaml or json often contain dashes, which are not valid in env names.
+	NormalizeNames bool
+
+	// Observer is optional and if set, is notified every time FetchEnv is
+	// called, for ex. to export metrics about config loading.
+	Observer Observer
+
+	// Sources is optional and if set, the envs are looked up in each of the
+	// sources in order and the first source that has the env set is used. It
+	// is defaulted to only the Environment of the process, which needs to be
+	// included explicitly if other sources are set.
+	Sources []Source
+
+	// SourceTagName is optional and if set, it will be used as the tag name
+	// that restricts which of the Sources a field can be fetched from, by the
+	// name of the source. For ex. `source:"vault"` guarantees that the field is
+	// fetched from the secret store, even if an env with the same name is set.
+	// Multiple sources can be separated by a comma.
+	SourceTagName string
+
+	// SourceNameTags is optional and if set, are the names of sources that a
+	// field can give its own name within, through a tag with the same name as
+	// the source. For ex. with "vault" set, the field
+	// `vault:"secret/data/app#db_password"` is looked up in the vault source
+	// under that name rather than its env names. Other envs can be referenced
+	// within the name as ${NAME}, which are looked up in the Sources, for ex.
+	// `vault:"secret/data/${APP_ENV}/app#db_password"`.
+	SourceNameTags []string
+
+	// DescriptionTagName is optional and if set, it will be used as the tag
+	// name that holds the description of each field, which is used when
+	// rendering help text. It is defaulted to "desc".
+	DescriptionTagName string
+
+	// Prompter is optional and if set, it is asked for the value of any
+	// required env that is not set instead of failing, for ex. to prompt the
+	// user on the first run of a CLI tool. It is only used for required
+	// fields.
+	Prompter Prompter
+
+	// FileSuffix is optional and if set, any field whose envs are not set is
+	// read from the file at the path set in one of its envs with the suffix
+	// appended, for ex. with "_FILE" the field `env:"db_password"` is read from
+	// the file at PREFIX_DB_PASSWORD_FILE. This is the convention used for
+	// Docker secrets.
+	FileSuffix string
+
+	// DefaultTagName is optional and if set, it will be used as the tag name
+	// that holds the default value of each field, for ex. `default:"8080"`.
+	// When none of the envs of the field are set, the default is parsed into
+	// the field the same way as the value of an env. A field with a default is
+	// never reported as missing, even when it is required.
+	DefaultTagName string
+
+	// EnumTagName is optional and if set, it will be used as the tag name that
+	// lists the only values a field can be set to, separated by a comma, for
+	// ex. `enum:"debug,info,warn,error"`. FetchEnv fails if the field is set to
+	// any other value. The values are included in the JSONSchema.
+	EnumTagName string
+
+	// ProfileEnv is optional and if set, is the name of the env that selects
+	// the active profile, for ex. "APP_PROFILE". When a profile is active, the
+	// envs scoped to it are looked up before the unscoped ones, with the
+	// profile placed after the prefix. For ex. with the profile "dev", the
+	// field `env:"host"` is fetched from APP_DEV_HOST before APP_HOST. The env
+	// is looked up in the Sources, the same as any other env.
+	ProfileEnv string
+
+	// Profiles is optional and if set, are the only profiles that can be
+	// selected through the ProfileEnv, so that a typo in the profile does not
+	// silently fall back to the unscoped envs.
+	Profiles []string
+
+	// ProfilesTagName is optional and if set, it will be used as the tag name
+	// that restricts which profiles a field is resolved in, for ex.
+	// `profiles:"prod,staging"`. Outside of those profiles the field is left
+	// untouched and is never required. Adding "optional" to the list, for ex.
+	// `profiles:"prod,optional"`, resolves the field in every profile but only
+	// requires it in the profiles listed. It is defaulted to "profiles".
+	ProfilesTagName string
+
+	// schema is set when fetching through a Schema, so that its compiled
+	// fields are used rather than walking through the struct again
+	schema *Schema
+
+	// growCollections is set when resolving the envs to fetch, so that slices
+	// and maps of structs are grown to fit every element set within the
+	// sources rather than only walking through the elements they already have
+	growCollections bool
+}
+
+// nameNormalizer replaces the characters that are normalized to underscores
+// when NormalizeNames is on
+var nameNormalizer = strings.NewReplacer("-", "_", ".", "_")
+
+// normalizeName will normalize the name if NormalizeNames is on
+func (e Envstruct) normalizeName(name string) string {
+	if !e.NormalizeNames {
+		return name
+	}
+
+	return nameNormalizer.Replace(name)
+}
+
+// ConflictError can be used as the OnConflict function to fail FetchEnv if an
+// env would overwrite a field that is already set.
+func ConflictError(field string, envName string) error {
+	return fmt.Errorf("env %s would overwrite the value already set on field %s", envName, field)
+}
+
+// Errors is returned by FetchEnv when BestEffort is on and one or more fields
+// failed to be fetched. Every other field will have still been set.
+type Errors []error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d field(s) failed to be fetched from env: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors, so that errors.Is and errors.As find any of them
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// collect will add the error onto the list of errors. If the error is itself a
+// list of errors, then it is flattened so that nested structs do not produce
+// nested lists.
+func (e Errors) collect(err error) Errors {
+	if err == nil {
+		return e
+	}
+
+	if errs, ok := err.(Errors); ok {
+		return append(e, errs...)
+	}
+
+	return append(e, err)
+}
+
+// errorOrNil returns nil if there are no errors, so that an empty list is not
+// returned as a non-nil error interface.
+func (e Errors) errorOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// FieldError is returned when the value of a field fails to be parsed. It
+// wraps the error from the Parser with the path to the field and where the
+// value came from, so that the culprit can be found within large structs.
+type FieldError struct {
+	// Path is the path to the field within the struct, for ex. "Server.Port"
+	Path string
+
+	// Env is the name of the env that the value was fetched from. It is empty
+	// if the value was not fetched from an env, for ex. through the Overrides.
+	Env string
+
+	// Source is the name of the source that the value was fetched from
+	Source string
+
+	// Err is the error that parsing the value failed with
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	from := "env " + e.Env
+	if e.Env == "" {
+		from = e.Source
+	}
+
+	return fmt.Sprintf("field %s (%s): %s", e.Path, from, e.Err)
+}
+
+// Unwrap returns the error that parsing the value failed with
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Prefixer can be implemented by a struct to declare its own prefix. This
+// allows libraries to ship config structs with a built in namespace rather
+// than relying on every caller to set the same Prefix. If the Prefix is set on
+// the Envstruct, it takes precedence over the prefix declared by the struct.
+type Prefixer interface {
+	EnvPrefix() string
 }
 
 // FetchEnv will fetch environment variables and appropriately set them into
 // the struct given. The details on how the environemnt variables will be
 // fetched is dictated by field tags. Nested tags are supported. It will
-// overwrite the struct with any env values set.
+// overwrite the struct with any env values set. Every env is fetched and parsed
+// before any of them are set, so if an error is returned the struct is left
+// untouched (unless BestEffort is on). Once the struct is populated, Derive is
+// called on any structs that implement Deriver, and an error from it is
+// returned after the envs have already been set.
 func (e Envstruct) FetchEnv(object interface{}) error {
 	// Check if the object is a struct
 	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
 		return errors.New("failed to parse env into object, needs to be type struct")
 	}
 
-	// Uppercase the prefix value
-	envPrefix := strings.ToUpper(e.Prefix)
+	start := time.Now()
 
-	// Loop through each field within the struct
-	v := reflect.ValueOf(object).Elem()
-	for i := 0; i < v.NumField(); i++ {
-		// Start building up the string that will be used to fetch the env. It
-		// starts with the prefix (if set) and can contain any nested struct tag
-		// values and field tag values.
-		var envNameBuilder []string
-		if e.Prefix != "" {
-			envNameBuilder = []string{envPrefix}
-		}
+	p, err := e.fetchEnv(object)
+	e.observe(start, p, err)
 
-		// Extract the tag from the field value and use it to fetch the env into
-		// the struct
-		err := e.extractTag(envNameBuilder, v.Type().Field(i), v.Field(i))
-		if err != nil {
-			return err
+	return err
+}
+
+func (e Envstruct) fetchEnv(object interface{}) (*plan, error) {
+	// Fetch and parse the envs into a plan. Nothing is set on the struct yet so
+	// that it is not left half populated if any of the fields fail.
+	p, err := e.resolve(object)
+	if err != nil && !e.BestEffort {
+		return nil, err
+	}
+
+	// Every field was successfully parsed (or we are in best effort mode, where
+	// every field that parsed should be set), so apply the plan onto the struct
+	p.apply()
+
+	// Now that the struct is populated, any derived fields can be computed
+	deriveErr := derive(reflect.ValueOf(object), nil)
+	if deriveErr != nil {
+		if !e.BestEffort {
+			return p, deriveErr
 		}
+
+		return p, Errors{}.collect(err).collect(deriveErr).errorOrNil()
 	}
 
-	return nil
+	return p, err
 }
 
-func (e Envstruct) extractTag(envNameBuilder []string, fieldDescription reflect.StructField, fieldValue reflect.Value) error {
-	// Fetch the tag value from the struct and append it to the string that will
-	// be used to fetch the env value
-	tagValue, found := fieldDescription.Tag.Lookup(e.TagName)
-	if found {
-		includeTag := true
+// resolve will loop through each field within the struct, extracting the tag
+// from the field value and using it to fetch and parse the env into a plan
+// that can later be applied onto the struct.
+func (e Envstruct) resolve(object interface{}) (*plan, error) {
+	var errs Errors
+
+	// Read every source that can be listed up front, so that each candidate
+	// name of each field is looked up within a map rather than the source
+	sources, err := e.indexSources()
+	if err != nil {
+		return nil, err
+	}
 
-		if e.IgnoreTagName != "" {
-			ignore, found := fieldDescription.Tag.Lookup(e.IgnoreTagName)
+	e.Sources = sources
 
-			if found {
-				ignoreBool, err := strconv.ParseBool(ignore)
-				if err != nil {
-					return err
-				}
+	// Find every field within the struct that can be fetched from the env
+	e.growCollections = true
 
-				if ignoreBool {
-					includeTag = false
-				}
-			}
+	var fields []*field
+	if e.schema != nil && !e.schema.dynamic {
+		fields = e.schema.fieldsOf(object)
+	} else {
+		fields, err = e.fields(object)
+	}
+	if err != nil {
+		if !e.BestEffort {
+			return nil, err
 		}
 
-		if includeTag {
-			// Removes any string after a comma within the tag value
-			if e.StripValue {
-				// Split up the tag value string into a slice where each element is
-				// separated by a comma
-				strippedTagValueSlice := strings.SplitAfter(tagValue, ",")
+		errs = errs.collect(err)
+	}
 
-				// Remove the comma from the first value within the slice (which is the
-				// tag value we are looking for)
-				tagValue = strings.TrimRight(strippedTagValueSlice[0], ",")
+	// Fetch the env for each field into the plan
+	p := &plan{}
+	for _, f := range fields {
+		err := e.fetchField(p, f)
+		if err != nil {
+			if !e.BestEffort {
+				return p, err
 			}
 
-			if tagValue != "" {
-				envNameBuilder = append(envNameBuilder, strings.ToUpper(tagValue))
-			}
+			errs = errs.collect(err)
 		}
 	}
 
-	// If the field is a struct then loop through each field and recurse
-	if fieldDescription.Type.Kind() == reflect.Struct {
-		for i := 0; i < fieldValue.NumField(); i++ {
-			err := e.extractTag(envNameBuilder, fieldValue.Type().Field(i), fieldValue.Field(i))
-			if err != nil {
-				return err
+	// Make sure that every override was used, otherwise a typo in the path of an
+	// override would silently be ignored
+	if len(p.overridden) != len(e.Overrides) {
+		var unknown []string
+		for fieldPath := range e.Overrides {
+			if !containsString(p.overridden, fieldPath) {
+				unknown = append(unknown, fieldPath)
 			}
 		}
-	} else if fieldDescription.Type.Kind() == reflect.Ptr && fieldDescription.Type.Elem().Kind() == reflect.Struct {
-		if !fieldValue.IsNil() {
-			for i := 0; i < fieldValue.Elem().NumField(); i++ {
-				err := e.extractTag(envNameBuilder, fieldValue.Elem().Type().Field(i), fieldValue.Elem().Field(i))
-				if err != nil {
-					return err
-				}
+
+		sort.Strings(unknown)
+
+		err := fmt.Errorf("overrides set for unknown fields: %s", strings.Join(unknown, ", "))
+		if !e.BestEffort {
+			return p, err
+		}
+
+		errs = errs.collect(err)
+	}
+
+	// Make sure that every env set with the prefix is used by a field, otherwise
+	// a typo in the name of an env would silently be ignored
+	if e.RejectUnknown {
+		if unknown := e.unknownEnvs(object, fields); len(unknown) > 0 {
+			err := fmt.Errorf("envs set for unknown fields: %s", strings.Join(unknown, ", "))
+			if !e.BestEffort {
+				return p, err
 			}
+
+			errs = errs.collect(err)
 		}
-	} else {
-		// If the field is not a struct, fetch the environment variable value using
-		// the built up string
-		envNames := []string{strings.Join(envNameBuilder, "_")}
-
-		// If there is an override tag set, try to see if this field has the
-		// override set. If it does then use that value to fetch the env with
-		if e.OverrideName != "" {
-			if override, found := fieldDescription.Tag.Lookup(e.OverrideName); found {
-				envNames = strings.Split(override, ",")
+	}
+
+	// Every raw value has now been fetched, so any references to other fields
+	// can be interpolated
+	if e.Interpolate {
+		err = p.interpolate(reflect.ValueOf(object).Elem(), e.BestEffort)
+		if err != nil {
+			if !e.BestEffort {
+				return p, err
 			}
+
+			errs = errs.collect(err)
 		}
+	}
 
-		// Fetch the env
-		for _, envName := range envNames {
-			value := os.Getenv(strings.TrimSpace(envName))
+	// Every raw value is now final, so they can be parsed
+	err = p.parse(e)
+	if err != nil {
+		if !e.BestEffort {
+			return p, err
+		}
 
-			// If the env is found, parse the fetched env value and set it on the field
-			if value != "" {
-				err := e.Parser.ParseInto(fieldValue.Addr().Interface(), value)
+		errs = errs.collect(err)
+	}
+
+	return p, errs.errorOrNil()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchField will fetch the env for the field and stage it onto the plan
+func (e Envstruct) fetchField(p *plan, f *field) error {
+	// A slice or map of structs that has been grown is replaced as it is, the
+	// envs of its elements are fetched through their own fields
+	if f.elements.IsValid() {
+		p.grow(f)
+
+		return nil
+	}
+
+	// If the field has been pinned to a value through the Overrides, it takes
+	// precedence over any env
+	if value, ok := e.Overrides[f.path]; ok {
+		p.overridden = append(p.overridden, f.path)
+		p.stage(f, "override "+f.path, SourceOverride, value)
+
+		return nil
+	}
+
+	// Make sure that the sources the field is pinned to exist, otherwise the
+	// field could never be fetched
+	for _, name := range f.sources {
+		if !e.hasSource(name) {
+			return fmt.Errorf("field %s is pinned to source %s, which is not one of the sources", f.path, name)
+		}
+	}
+
+	sourceNames, err := e.expandSourceNames(f)
+	if err != nil {
+		return err
+	}
+
+	// A field that only has names within the sources is still looked up in
+	// them, even though it has no env names
+	envNames := f.envNames
+	if len(envNames) == 0 && len(sourceNames) > 0 {
+		envNames = []string{""}
+	}
+
+	// Fetch the env, falling back to the deprecated envs of the field
+	for i, envName := range append(envNames[:len(envNames):len(envNames)], f.deprecatedNames...) {
+		value, source, err := e.lookup(envName, f.sources, sourceNames)
+		if err != nil {
+			return err
+		}
+
+		// If the env is found, stage the fetched env value to be parsed and set on
+		// the field
+		if value != "" {
+			if e.OnConflict != nil && !f.value.IsZero() {
+				err := e.OnConflict(f.path, envName)
 				if err != nil {
 					return err
 				}
+			}
+
+			if i >= len(envNames) {
+				e.warnDeprecated(f, envName)
+			}
+
+			if name, ok := sourceNames[source]; ok {
+				envName = name
+			}
+
+			p.stage(f, envName, source, value)
+
+			return nil
+		}
+	}
+
+	// None of the envs were found, so fall back to the file that the env with
+	// the FileSuffix points to
+	value, name, source, err := e.lookupFileSuffix(f)
+	if err != nil {
+		return err
+	}
+
+	if value != "" {
+		p.stage(f, name, source, value)
+
+		return nil
+	}
+
+	// None of the envs were found, so fall back to the default of the field
+	if value, found := e.defaultValue(f); found {
+		p.stage(f, "default "+f.path, SourceDefault, value)
+
+		return nil
+	}
+
+	// None of the envs were found, which is only a problem if the field is
+	// required
+	if e.required(f) {
+		// Give the user a chance to enter the value instead of failing
+		if e.Prompter != nil && len(f.envNames) > 0 {
+			value, err := e.Prompter.Prompt(f.envNames[0], e.description(f), f.options.secret)
+			if err != nil {
+				return fmt.Errorf("failed to prompt for %s: %w", f.envNames[0], err)
+			}
 
-				break
+			if value != "" {
+				p.stage(f, f.envNames[0], SourcePrompt, value)
+				return nil
 			}
 		}
+
+		return fmt.Errorf("required env %s is not set", strings.Join(f.envNames, " or "))
 	}
 
 	return nil
 }
 
+// required returns true if the field needs one of its envs to be set
+func (e Envstruct) required(f *field) bool {
+	if !f.tagged || isOptional(f.description.Type) {
+		return false
+	}
+
+	return f.options.required || (e.RequireAll && !f.options.optional)
+}
+
+// defaultValue returns the default value of the field from the
+// DefaultTagName, if it has one
+func (e Envstruct) defaultValue(f *field) (string, bool) {
+	if e.DefaultTagName == "" || !f.tagged {
+		return "", false
+	}
+
+	return f.description.Tag.Lookup(e.DefaultTagName)
+}
+
+// parse will parse the value into a new variable of the given type, which can
+// then be staged to be set onto the field once every field has been parsed.
+// The options are the tag options of the field.
+func (e Envstruct) parse(fieldType reflect.Type, value string, options tagOptions) (reflect.Value, error) {
+	return e.fieldParser(options).ParseValue(fieldType, value)
+}
+
+// fieldParser returns the Parser with the tag options of the field applied,
+// such as its format or its own delimiter and separator
+func (e Envstruct) fieldParser(options tagOptions) Parser {
+	parser := e.Parser
+	parser.format = options.format
+
+	if options.delimiter != "" {
+		parser.Delimiter = options.delimiter
+	}
+
+	if options.separator != "" {
+		parser.Separator = options.separator
+	}
+
+	return parser
+}
+
 type Parser struct {
 	// Delimiter is used as the separater for multiple values within a struct or
 	// map. It is defaulted to a comma ",". It is used so that in the environment
@@ -183,6 +681,29 @@ type Parser struct {
 	Delimiter string
 
 	Unmarshaler UnmarshalFunc
+
+	// Location is optional and if set, is used as the time zone when parsing
+	// time.Time values that do not contain an explicit offset. For example,
+	// "2024-06-01 03:00" will be interpreted in this location rather than UTC.
+	Location *time.Location
+
+	// Separator is used as the separator between the key and value of each
+	// entry of a map. It is defaulted to a colon ":", for ex.
+	// "PREFIX_FIELD=foo:1,bar:2".
+	Separator string
+
+	// OuterDelimiter is used as the separator for the items of nested slices
+	// and maps, which are then split on the Delimiter. It is defaulted to a
+	// semicolon ";", so that for ex. "PREFIX_FIELD=a,b;c,d" populates a
+	// [][]string with two slices.
+	OuterDelimiter string
+
+	// format is the format set through the "format" tag option of the field
+	// being parsed, for ex. "iso8601"
+	format string
+
+	// inner is set when parsing an item of a nested slice or map
+	inner bool
 }
 
 type UnmarshalFunc func([]byte, interface{}) error
@@ -190,43 +711,87 @@ type UnmarshalFunc func([]byte, interface{}) error
 // ParseInto will parse the value given into the fieldValue. If the value is a
 // slice or a map, it will manually separate each item within the array of
 // items and pass them to the unmarshaler. If not, the value will be directly
-// passed to the unmarshaller.
+// passed to the unmarshaller. Values that implement encoding.TextUnmarshaler
+// are parsed with UnmarshalText instead of the unmarshaler.
 //
-// IMPORTANT: It currently DOES NOT SUPPORT NESTED SLICES OR MAPS. For ex,
-// "[][]string" will not be parsed correctly.
+// Slices and maps of slices or maps, for ex. "[][]string" or
+// "map[string][]string", are first split on the OuterDelimiter and then each
+// item is split on the Delimiter, for ex. "a,b;c,d". Only one level of nesting
+// is supported.
 func (p Parser) ParseInto(fieldValue interface{}, value string) error {
 	if p.Unmarshaler == nil {
 		return errors.New("no unmarshaler set for parser")
 	}
 
-	// Default delimiter is comma
-	delimiter := ","
-	if p.Delimiter != "" {
-		delimiter = p.Delimiter
+	delimiter := p.delimiter()
+
+	// Optional fields parse the value into their Value and record that it was
+	// set
+	if optional, ok := fieldValue.(optionalParser); ok {
+		return optional.parseOptional(p, value)
 	}
 
 	fieldType := reflect.TypeOf(fieldValue).Elem()
 
+	// Slices and maps that implement encoding.TextUnmarshaler, such as net.IP,
+	// parse the whole value themselves
+	if isTextUnmarshaler(fieldType) {
+		return p.unmarshal([]byte(value), fieldValue)
+	}
+
+	// Nested slices and maps are split on the OuterDelimiter, with each item
+	// parsed as a slice or map of its own
+	nested := isNestedCollection(fieldType)
+	if nested {
+		if p.inner {
+			return errors.New("slices and maps can only be nested one level deep")
+		}
+
+		delimiter = p.outerDelimiter()
+	}
+
+	inner := p
+	inner.inner = true
+
+	// parseItem parses a single item of the slice or map. Quoted items are set
+	// as they are on strings, rather than being unmarshaled.
+	parseItem := func(i item, v interface{}) error {
+		if nested {
+			return inner.ParseInto(v, i.value)
+		}
+
+		target := reflect.ValueOf(v).Elem()
+		if i.quoted && target.Kind() == reflect.String && !isTextUnmarshaler(target.Type()) {
+			target.SetString(i.value)
+			return nil
+		}
+
+		return p.unmarshal([]byte(strings.TrimSpace(i.value)), v)
+	}
+
 	// Two special types of fields that we have to manually parse is a slice and
-	// a map. XXX: Will we ever need to parse nested slices/maps?
+	// a map
 	switch fieldType.Kind() {
 	case reflect.Slice:
 		// Split the field value into separate elements in a slice
-		envSlice := strings.Split(fmt.Sprintf("%v", value), delimiter)
+		envSlice, err := splitItems(value, delimiter)
+		if err != nil {
+			return err
+		}
 
 		// Make an empty slice that is the same type as the field in the struct
 		unmarshalledSlice := reflect.MakeSlice(fieldType, 0, 0)
 
 		// Loop through each element within the split string
-		for _, s := range envSlice {
+		for i, s := range envSlice {
 			// Create a variable that is the same type of the individual slice
 			// elements
 			elem := reflect.New(fieldType.Elem())
 
 			// Unmarshal the env into the interface of the element
-			err := p.Unmarshaler([]byte(strings.TrimSpace(s)), elem.Interface())
+			err := parseItem(s, elem.Interface())
 			if err != nil {
-				return err
+				return elementError(fmt.Sprintf("element %d", i), err)
 			}
 
 			// Append each unmarshalled value into the unmarshalled slice. When
@@ -241,33 +806,39 @@ func (p Parser) ParseInto(fieldValue interface{}, value string) error {
 
 	case reflect.Map:
 		// Split the field value into separate key,value pairs in a map
-		envMap := strings.Split(fmt.Sprintf("%v", value), delimiter)
+		envMap, err := splitItems(value, delimiter)
+		if err != nil {
+			return err
+		}
 
 		// Make an empty map that is the same type as the field in the struct
 		unmarshalledMap := reflect.MakeMap(fieldType)
 		for _, envPair := range envMap {
-			// Split the map into the key and value
-			keyVal := strings.Split(fmt.Sprintf("%v", envPair), ":")
-			if len(keyVal) > 2 {
-				return errors.New(fmt.Sprintf("failed to parse map value %v", envPair))
+			// Split the map into the key and value on the first separator, so that
+			// the value can contain the separator itself, for ex. a URL with a port
+			rawKey, rawValue, found := strings.Cut(envPair.value, p.separator())
+			if !found {
+				return elementError(fmt.Sprintf("entry %q", envPair.value), errors.New("failed to parse map value"))
 			}
 
+			rawKey = strings.TrimSpace(rawKey)
+
 			// Create a variable that is the same type of the key type
 			key := reflect.New(fieldType.Key())
 
 			// Unmarshal the env into the key variable
-			err := p.Unmarshaler([]byte(strings.TrimSpace(keyVal[0])), key.Interface())
+			err := p.unmarshal([]byte(rawKey), key.Interface())
 			if err != nil {
-				return err
+				return elementError(fmt.Sprintf("key %q", rawKey), err)
 			}
 
 			// Create a variable that is the same type of the value type
 			value := reflect.New(fieldType.Elem())
 
 			// Unmarshal the env into the value variable
-			err = p.Unmarshaler([]byte(strings.TrimSpace(keyVal[1])), value.Interface())
+			err = parseItem(item{value: rawValue}, value.Interface())
 			if err != nil {
-				return err
+				return elementError(fmt.Sprintf("value of key %q", rawKey), err)
 			}
 
 			// Set the key and value on the unmarshalled map. When setting the key
@@ -279,7 +850,7 @@ func (p Parser) ParseInto(fieldValue interface{}, value string) error {
 		// Set the unmarshalled map onto the map struct field
 		reflect.ValueOf(fieldValue).Elem().Set(unmarshalledMap)
 	default:
-		err := p.Unmarshaler([]byte(value), fieldValue)
+		err := p.unmarshal([]byte(value), fieldValue)
 		if err != nil {
 			return err
 		}
@@ -287,3 +858,73 @@ func (p Parser) ParseInto(fieldValue interface{}, value string) error {
 
 	return nil
 }
+
+// ParseValue will parse the raw value into a new value of the given type, the
+// same way that the value of an env is parsed before it is set on a field.
+func (p Parser) ParseValue(valueType reflect.Type, raw string) (reflect.Value, error) {
+	parsed := reflect.New(valueType)
+	err := p.ParseInto(parsed.Interface(), raw)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return parsed.Elem(), nil
+}
+
+// ParseValue will parse the raw value into a new value of the given type using
+// a Parser with the default delimiter and Unmarshal as its Unmarshaler. It
+// does not touch the environment, which makes it useful for checking how a
+// value would be parsed, for ex. when fuzzing.
+func ParseValue(valueType reflect.Type, raw string) (reflect.Value, error) {
+	return Parser{Unmarshaler: Unmarshal}.ParseValue(valueType, raw)
+}
+
+// delimiter returns the Delimiter, which is defaulted to a comma
+func (p Parser) delimiter() string {
+	if p.Delimiter != "" {
+		return p.Delimiter
+	}
+
+	return ","
+}
+
+// separator returns the Separator, which is defaulted to a colon
+func (p Parser) separator() string {
+	if p.Separator != "" {
+		return p.Separator
+	}
+
+	return ":"
+}
+
+// outerDelimiter returns the OuterDelimiter, which is defaulted to a semicolon
+func (p Parser) outerDelimiter() string {
+	if p.OuterDelimiter != "" {
+		return p.OuterDelimiter
+	}
+
+	return ";"
+}
+
+// isNestedCollection returns true if the type is a slice or map whose items
+// are slices or maps themselves, other than []byte and types that implement
+// encoding.TextUnmarshaler, which are parsed as single values
+func isNestedCollection(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Map {
+		return false
+	}
+
+	elem := t.Elem()
+	if isTextUnmarshaler(elem) || (elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.Uint8) {
+		return false
+	}
+
+	return elem.Kind() == reflect.Slice || elem.Kind() == reflect.Map
+}
+
+// elementError wraps an error that occurred while parsing a single element of
+// a slice or map so that the offending element can be found easily, for ex.
+// "element 3: ...".
+func elementError(element string, err error) error {
+	return fmt.Errorf("%s: %w", element, err)
+}
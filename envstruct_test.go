@@ -1,8 +1,10 @@
 package envstruct_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,21 +31,47 @@ type EnvstructTest struct {
 
 	Prefix        string
 	TagName       string
+	TagNames      []string
 	OverrideName  string
 	IgnoreTagName string
 	Delimiter     string
+	Location      *time.Location
 	StripValue    bool
+	BestEffort    bool
+	RequireAll    bool
+	OnConflict    func(string, string) error
+	Overrides     map[string]string
+	Interpolate   bool
+	Normalize     bool
+	DefaultTag    string
 
 	EnvValues map[string]interface{}
 
 	TestStruct   interface{}
 	ResultStruct interface{}
+
+	// Error is the expected error message, if FetchEnv is expected to fail
+	Error string
+}
+
+type PrefixedStruct struct {
+	Field1 string `tag:"field1"`
+}
+
+func (PrefixedStruct) EnvPrefix() string {
+	return "lib"
 }
 
 func createString(x string) *string {
 	return &x
 }
 
+func createTime(x time.Time) *time.Time {
+	return &x
+}
+
+var newYork, _ = time.LoadLocation("America/New_York")
+
 func (s *EnvstructSuite) TestEnvstruct() {
 	for _, t := range []EnvstructTest{
 		{
@@ -634,28 +662,1088 @@ func (s *EnvstructSuite) TestEnvstruct() {
 				},
 			},
 		},
-	} {
-		s.Run(t.It, func() {
-			env := envstruct.Envstruct{
-				Prefix:        t.Prefix,
-				TagName:       t.TagName,
-				OverrideName:  t.OverrideName,
-				IgnoreTagName: t.IgnoreTagName,
-				StripValue:    t.StripValue,
+		{
+			It: "allocates structs that are a nil pointer when one of their envs is set",
 
-				Parser: envstruct.Parser{Delimiter: t.Delimiter, Unmarshaler: yaml.Unmarshal},
-			}
+			Prefix:  "prefix",
+			TagName: "tag",
 
-			for name, value := range t.EnvValues {
-				os.Setenv(name, fmt.Sprintf("%v", value))
-			}
+			EnvValues: map[string]interface{}{
+				"PREFIX_DATABASE_HOST": "localhost",
+			},
 
-			err := env.FetchEnv(t.TestStruct)
-			s.NoError(err)
+			TestStruct: &struct {
+				Database *struct {
+					Host string `tag:"host"`
+					Port int    `tag:"port"`
+				} `tag:"database"`
+				Cache *struct {
+					Host string `tag:"host"`
+				} `tag:"cache"`
+			}{},
 
-			assert.Equal(s.T(), t.TestStruct, t.ResultStruct, "the struct should have correct env values populated")
+			ResultStruct: &struct {
+				Database *struct {
+					Host string `tag:"host"`
+					Port int    `tag:"port"`
+				} `tag:"database"`
+				Cache *struct {
+					Host string `tag:"host"`
+				} `tag:"cache"`
+			}{
+				Database: &struct {
+					Host string `tag:"host"`
+					Port int    `tag:"port"`
+				}{
+					Host: "localhost",
+				},
+			},
+		},
+		{
+			It: "excludes fields tagged with a dash",
 
-			os.Clearenv()
-		})
-	}
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_-":      "value",
+				"PREFIX_-_HOST": "value",
+			},
+
+			TestStruct: &struct {
+				Field  string `tag:"-"`
+				Client struct {
+					Host string `tag:"host"`
+				} `tag:"-"`
+			}{},
+
+			ResultStruct: &struct {
+				Field  string `tag:"-"`
+				Client struct {
+					Host string `tag:"host"`
+				} `tag:"-"`
+			}{},
+		},
+		{
+			It: "includes the element index when a slice element fails to parse",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_PORTS": "80,443,http,8080",
+			},
+
+			TestStruct: &struct {
+				Ports []int `tag:"ports"`
+			}{},
+
+			ResultStruct: &struct {
+				Ports []int `tag:"ports"`
+			}{},
+
+			Error: "field Ports (env PREFIX_PORTS): element 2: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `http` into int",
+		},
+		{
+			It: "includes the key when a map value fails to parse",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_LIMITS": "cpu:1,memory:lots",
+			},
+
+			TestStruct: &struct {
+				Limits map[string]int `tag:"limits"`
+			}{},
+
+			ResultStruct: &struct {
+				Limits map[string]int `tag:"limits"`
+			}{},
+
+			Error: "field Limits (env PREFIX_LIMITS): value of key \"memory\": yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `lots` into int",
+		},
+		{
+			It: "includes the entry when a map entry is malformed",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_LIMITS": "cpu:1,memory",
+			},
+
+			TestStruct: &struct {
+				Limits map[string]int `tag:"limits"`
+			}{},
+
+			ResultStruct: &struct {
+				Limits map[string]int `tag:"limits"`
+			}{},
+
+			Error: "field Limits (env PREFIX_LIMITS): entry \"memory\": failed to parse map value",
+		},
+		{
+			It: "splits map entries on the first separator",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_ENDPOINTS": "api:https://example.com:443,auth:http://auth",
+			},
+
+			TestStruct: &struct {
+				Endpoints map[string]string `tag:"endpoints"`
+			}{},
+
+			ResultStruct: &struct {
+				Endpoints map[string]string `tag:"endpoints"`
+			}{
+				Endpoints: map[string]string{"api": "https://example.com:443", "auth": "http://auth"},
+			},
+		},
+		{
+			It: "does not set any fields if one of them fails to parse",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "value",
+				"PREFIX_FIELD2": "notanint",
+				"PREFIX_FIELD3": "value3",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 int    `tag:"field2"`
+				Field3 string `tag:"field3"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 int    `tag:"field2"`
+				Field3 string `tag:"field3"`
+			}{},
+
+			Error: "field Field2 (env PREFIX_FIELD2): yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `notanint` into int",
+		},
+		{
+			It: "populates every field it can and reports the failures in best effort mode",
+
+			Prefix:     "prefix",
+			TagName:    "tag",
+			BestEffort: true,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1":        "value",
+				"PREFIX_FIELD2":        "notanint",
+				"PREFIX_NESTED_FIELD3": "value3",
+				"PREFIX_NESTED_FIELD4": "notabool",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 int    `tag:"field2"`
+				Nested struct {
+					Field3 string `tag:"field3"`
+					Field4 bool   `tag:"field4"`
+				} `tag:"nested"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 int    `tag:"field2"`
+				Nested struct {
+					Field3 string `tag:"field3"`
+					Field4 bool   `tag:"field4"`
+				} `tag:"nested"`
+			}{
+				Field1: "value",
+				Nested: struct {
+					Field3 string `tag:"field3"`
+					Field4 bool   `tag:"field4"`
+				}{
+					Field3: "value3",
+				},
+			},
+
+			Error: "2 field(s) failed to be fetched from env: field Field2 (env PREFIX_FIELD2): yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `notanint` into int; field Nested.Field4 (env PREFIX_NESTED_FIELD4): yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `notabool` into bool",
+		},
+		{
+			It: "errors when a tagged field is not set and all fields are required",
+
+			Prefix:     "prefix",
+			TagName:    "tag",
+			RequireAll: true,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "value",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{},
+
+			Error: "required env PREFIX_FIELD2 is not set",
+		},
+		{
+			It: "lists every override name when a required field is not set",
+
+			Prefix:       "prefix",
+			TagName:      "tag",
+			OverrideName: "override",
+			RequireAll:   true,
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1" override:"FIELD_ONE, FIELD_1"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1" override:"FIELD_ONE, FIELD_1"`
+			}{},
+
+			Error: "required env FIELD_ONE or FIELD_1 is not set",
+		},
+		{
+			It: "errors when a field with the required option is not set",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "value",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+				Field3 string `tag:"field3|field_three,required"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+				Field3 string `tag:"field3|field_three,required"`
+			}{},
+
+			Error: "required env PREFIX_FIELD3 or PREFIX_FIELD_THREE is not set",
+		},
+		{
+			It: "sets a field with the required option from any of its envs",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD_THREE": "value",
+			},
+
+			TestStruct: &struct {
+				Field3 string `tag:"field3|field_three,required"`
+			}{},
+
+			ResultStruct: &struct {
+				Field3 string `tag:"field3|field_three,required"`
+			}{
+				Field3: "value",
+			},
+		},
+		{
+			It: "parses the default of a field when none of its envs are set",
+
+			Prefix:     "prefix",
+			TagName:    "tag",
+			DefaultTag: "default",
+			RequireAll: true,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "9090",
+			},
+
+			TestStruct: &struct {
+				Field1 int           `tag:"field1" default:"8080"`
+				Field2 int           `tag:"field2" default:"8080"`
+				Field3 []string      `tag:"field3" default:"a,b"`
+				Field4 time.Duration `tag:"field4" default:"30s"`
+				Field5 string        `tag:"field5,optional"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 int           `tag:"field1" default:"8080"`
+				Field2 int           `tag:"field2" default:"8080"`
+				Field3 []string      `tag:"field3" default:"a,b"`
+				Field4 time.Duration `tag:"field4" default:"30s"`
+				Field5 string        `tag:"field5,optional"`
+			}{
+				Field1: 9090,
+				Field2: 8080,
+				Field3: []string{"a", "b"},
+				Field4: 30 * time.Second,
+			},
+		},
+		{
+			It: "errors when the default of a field fails to be parsed",
+
+			Prefix:     "prefix",
+			TagName:    "tag",
+			DefaultTag: "default",
+
+			TestStruct: &struct {
+				Field1 int `tag:"field1" default:"notanint"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 int `tag:"field1" default:"notanint"`
+			}{},
+
+			Error: "field Field1 (default): yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `notanint` into int",
+		},
+		{
+			It: "ignores the default tag when the DefaultTagName is not set",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			TestStruct: &struct {
+				Field1 int `tag:"field1" default:"8080"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 int `tag:"field1" default:"8080"`
+			}{},
+		},
+		{
+			It: "allows fields to opt out of being required",
+
+			Prefix:     "prefix",
+			TagName:    "tag",
+			RequireAll: true,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_NESTED_FIELD1": "value",
+			},
+
+			TestStruct: &struct {
+				Nested struct {
+					Field1 string `tag:"field1"`
+					Field2 string `tag:"field2,optional"`
+				} `tag:"nested"`
+				Field3 string
+			}{},
+
+			ResultStruct: &struct {
+				Nested struct {
+					Field1 string `tag:"field1"`
+					Field2 string `tag:"field2,optional"`
+				} `tag:"nested"`
+				Field3 string
+			}{
+				Nested: struct {
+					Field1 string `tag:"field1"`
+					Field2 string `tag:"field2,optional"`
+				}{
+					Field1: "value",
+				},
+			},
+		},
+		{
+			It: "errors when an env would overwrite a field that is already set",
+
+			Prefix:     "prefix",
+			TagName:    "tag",
+			OnConflict: envstruct.ConflictError,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_NESTED_FIELD1": "env",
+			},
+
+			TestStruct: &struct {
+				Nested struct {
+					Field1 string `tag:"field1"`
+				} `tag:"nested"`
+			}{
+				Nested: struct {
+					Field1 string `tag:"field1"`
+				}{
+					Field1: "code",
+				},
+			},
+
+			ResultStruct: &struct {
+				Nested struct {
+					Field1 string `tag:"field1"`
+				} `tag:"nested"`
+			}{
+				Nested: struct {
+					Field1 string `tag:"field1"`
+				}{
+					Field1: "code",
+				},
+			},
+
+			Error: "env PREFIX_NESTED_FIELD1 would overwrite the value already set on field Nested.Field1",
+		},
+		{
+			It: "overwrites fields that are already set when the conflict is only a warning",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+			OnConflict: func(string, string) error {
+				return nil
+			},
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "env",
+				"PREFIX_FIELD2": "env",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{
+				Field1: "code",
+			},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{
+				Field1: "env",
+				Field2: "env",
+			},
+		},
+		{
+			It: "tries each alias in the tag value in order with the prefix and nesting applied",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_DATABASE_ADDRESS": "old",
+				"PREFIX_DB_ADDRESS":       "new",
+				"PREFIX_DATABASE_PORT":    "5432",
+			},
+
+			TestStruct: &struct {
+				Database struct {
+					Addr string `tag:"addr|address"`
+					Port int    `tag:"port"`
+				} `tag:"db|database"`
+			}{},
+
+			ResultStruct: &struct {
+				Database struct {
+					Addr string `tag:"addr|address"`
+					Port int    `tag:"port"`
+				} `tag:"db|database"`
+			}{
+				Database: struct {
+					Addr string `tag:"addr|address"`
+					Port int    `tag:"port"`
+				}{
+					Addr: "new",
+					Port: 5432,
+				},
+			},
+		},
+		{
+			It: "lists every alias when a required field is not set",
+
+			Prefix:     "prefix",
+			TagName:    "tag",
+			RequireAll: true,
+
+			TestStruct: &struct {
+				Addr string `tag:"addr|address"`
+			}{},
+
+			ResultStruct: &struct {
+				Addr string `tag:"addr|address"`
+			}{},
+
+			Error: "required env PREFIX_ADDR or PREFIX_ADDRESS is not set",
+		},
+		{
+			It: "uses the prefix declared by the struct if no prefix is set",
+
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"LIB_FIELD1": "value",
+			},
+
+			TestStruct:   &PrefixedStruct{},
+			ResultStruct: &PrefixedStruct{Field1: "value"},
+		},
+		{
+			It: "uses the configured prefix over the prefix declared by the struct",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"LIB_FIELD1":    "lib",
+				"PREFIX_FIELD1": "value",
+			},
+
+			TestStruct:   &PrefixedStruct{},
+			ResultStruct: &PrefixedStruct{Field1: "value"},
+		},
+		{
+			It: "uses the overrides over any env",
+
+			Prefix:       "prefix",
+			TagName:      "tag",
+			OverrideName: "override",
+			Overrides: map[string]string{
+				"Field1":        "pinned",
+				"Nested.Field3": "1,2",
+			},
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1":        "env",
+				"PREFIX_FIELD2":        "env",
+				"PREFIX_NESTED_FIELD3": "3",
+				"OVERRIDE_FIELD3":      "3",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+				Nested struct {
+					Field3 []int `tag:"field3" override:"OVERRIDE_FIELD3"`
+				} `tag:"nested"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+				Nested struct {
+					Field3 []int `tag:"field3" override:"OVERRIDE_FIELD3"`
+				} `tag:"nested"`
+			}{
+				Field1: "pinned",
+				Field2: "env",
+				Nested: struct {
+					Field3 []int `tag:"field3" override:"OVERRIDE_FIELD3"`
+				}{
+					Field3: []int{1, 2},
+				},
+			},
+		},
+		{
+			It: "errors if an override is set for a field that does not exist",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+			Overrides: map[string]string{
+				"Field1": "pinned",
+				"Feild2": "typo",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{},
+
+			Error: "overrides set for unknown fields: Feild2",
+		},
+		{
+			It: "interpolates references to other fields in dependency order",
+
+			Prefix:      "prefix",
+			TagName:     "tag",
+			Interpolate: true,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_URL":         "{{.Scheme}}://{{ .Server.Addr }}/",
+				"PREFIX_SERVER_ADDR": "{{.Server.Host}}:{{.Server.Port}}",
+				"PREFIX_SERVER_HOST": "localhost",
+			},
+
+			TestStruct: &struct {
+				URL    string `tag:"url"`
+				Scheme string `tag:"scheme"`
+				Server struct {
+					Addr string `tag:"addr"`
+					Host string `tag:"host"`
+					Port int    `tag:"port"`
+				} `tag:"server"`
+			}{
+				Scheme: "https",
+				Server: struct {
+					Addr string `tag:"addr"`
+					Host string `tag:"host"`
+					Port int    `tag:"port"`
+				}{
+					Port: 8080,
+				},
+			},
+
+			ResultStruct: &struct {
+				URL    string `tag:"url"`
+				Scheme string `tag:"scheme"`
+				Server struct {
+					Addr string `tag:"addr"`
+					Host string `tag:"host"`
+					Port int    `tag:"port"`
+				} `tag:"server"`
+			}{
+				URL:    "https://localhost:8080/",
+				Scheme: "https",
+				Server: struct {
+					Addr string `tag:"addr"`
+					Host string `tag:"host"`
+					Port int    `tag:"port"`
+				}{
+					Addr: "localhost:8080",
+					Host: "localhost",
+					Port: 8080,
+				},
+			},
+		},
+		{
+			It: "does not interpolate unless it is turned on",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "copy of {{.Field2}}",
+				"PREFIX_FIELD2": "value",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{
+				Field1: "copy of {{.Field2}}",
+				Field2: "value",
+			},
+		},
+		{
+			It: "errors on interpolation cycles",
+
+			Prefix:      "prefix",
+			TagName:     "tag",
+			Interpolate: true,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "{{.Field2}}",
+				"PREFIX_FIELD2": "{{.Field1}}",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+				Field2 string `tag:"field2"`
+			}{},
+
+			Error: "interpolation cycle detected at field Field1",
+		},
+		{
+			It: "errors on references to unknown fields",
+
+			Prefix:      "prefix",
+			TagName:     "tag",
+			Interpolate: true,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "{{.Missing}}",
+			},
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1"`
+			}{},
+
+			Error: "PREFIX_FIELD1 references unknown field Missing",
+		},
+		{
+			It: "parses times without an offset in the configured location",
+
+			Prefix:   "prefix",
+			TagName:  "tag",
+			Location: newYork,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "2024-06-01 03:00",
+				"PREFIX_FIELD2": "2024-06-01T03:00:00Z",
+				"PREFIX_FIELD3": "2024-06-01",
+				"PREFIX_FIELD4": "2024-06-01T03:00:00, 2024-06-02T03:00:00+02:00",
+			},
+
+			TestStruct: &struct {
+				Field1 time.Time   `tag:"field1"`
+				Field2 time.Time   `tag:"field2"`
+				Field3 *time.Time  `tag:"field3"`
+				Field4 []time.Time `tag:"field4"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 time.Time   `tag:"field1"`
+				Field2 time.Time   `tag:"field2"`
+				Field3 *time.Time  `tag:"field3"`
+				Field4 []time.Time `tag:"field4"`
+			}{
+				Field1: time.Date(2024, 6, 1, 3, 0, 0, 0, newYork),
+				Field2: time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC),
+				Field3: createTime(time.Date(2024, 6, 1, 0, 0, 0, 0, newYork)),
+				Field4: []time.Time{
+					time.Date(2024, 6, 1, 3, 0, 0, 0, newYork),
+					time.Date(2024, 6, 2, 3, 0, 0, 0, time.FixedZone("", 2*60*60)),
+				},
+			},
+		},
+		{
+			It: "parses times without an offset in UTC if no location is configured",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "2024-06-01 03:00",
+			},
+
+			TestStruct: &struct {
+				Field1 time.Time `tag:"field1"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 time.Time `tag:"field1"`
+			}{
+				Field1: time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			It: "parses the timestamp forms of YAML",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "2024-6-1 3:04:05.5",
+				"PREFIX_FIELD2": "2024-6-1t3:04:05+02:00",
+				"PREFIX_FIELD3": "2024-6-1",
+			},
+
+			TestStruct: &struct {
+				Field1 time.Time `tag:"field1"`
+				Field2 time.Time `tag:"field2"`
+				Field3 time.Time `tag:"field3"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 time.Time `tag:"field1"`
+				Field2 time.Time `tag:"field2"`
+				Field3 time.Time `tag:"field3"`
+			}{
+				Field1: time.Date(2024, 6, 1, 3, 4, 5, 500000000, time.UTC),
+				Field2: time.Date(2024, 6, 1, 3, 4, 5, 0, time.FixedZone("", 2*60*60)),
+				Field3: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			It: "errors on times that cannot be parsed",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_FIELD1": "yesterday",
+			},
+
+			TestStruct: &struct {
+				Field1 time.Time `tag:"field1"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 time.Time `tag:"field1"`
+			}{},
+
+			Error: "field Field1 (env PREFIX_FIELD1): failed to parse \"yesterday\" as a time",
+		},
+		{
+			It: "normalizes dashes and dots into underscores",
+
+			Prefix:       "my-app",
+			TagName:      "tag",
+			OverrideName: "override",
+			Normalize:    true,
+
+			EnvValues: map[string]interface{}{
+				"MY_APP_HTTP_SERVER_READ_TIMEOUT": "1s",
+				"legacy_max_conns":                "10",
+			},
+
+			TestStruct: &struct {
+				Server struct {
+					ReadTimeout time.Duration `tag:"read-timeout"`
+					MaxConns    int           `tag:"max-conns" override:"legacy.max-conns"`
+				} `tag:"http.server"`
+			}{},
+
+			ResultStruct: &struct {
+				Server struct {
+					ReadTimeout time.Duration `tag:"read-timeout"`
+					MaxConns    int           `tag:"max-conns" override:"legacy.max-conns"`
+				} `tag:"http.server"`
+			}{
+				Server: struct {
+					ReadTimeout time.Duration `tag:"read-timeout"`
+					MaxConns    int           `tag:"max-conns" override:"legacy.max-conns"`
+				}{
+					ReadTimeout: time.Second,
+					MaxConns:    10,
+				},
+			},
+		},
+		{
+			It: "uses the first tag name that is found on each field",
+
+			Prefix:     "prefix",
+			TagNames:   []string{"env", "yaml", "json"},
+			StripValue: true,
+
+			EnvValues: map[string]interface{}{
+				"PREFIX_HOST":            "example.com",
+				"PREFIX_LISTEN_PORT":     "8080",
+				"PREFIX_DATABASE_DB_URL": "postgres://",
+			},
+
+			TestStruct: &struct {
+				Host     string `env:"host" yaml:"hostname"`
+				Port     int    `yaml:"listen_port,omitempty" json:"port"`
+				Database struct {
+					URL string `json:"db_url"`
+				} `yaml:"database"`
+			}{},
+
+			ResultStruct: &struct {
+				Host     string `env:"host" yaml:"hostname"`
+				Port     int    `yaml:"listen_port,omitempty" json:"port"`
+				Database struct {
+					URL string `json:"db_url"`
+				} `yaml:"database"`
+			}{
+				Host: "example.com",
+				Port: 8080,
+				Database: struct {
+					URL string `json:"db_url"`
+				}{
+					URL: "postgres://",
+				},
+			},
+		},
+		{
+			It: "does not fetch fields that are not tagged",
+
+			Prefix:  "prefix",
+			TagName: "tag",
+
+			EnvValues: map[string]interface{}{
+				"PREFIX":        "value",
+				"PREFIX_NESTED": "value",
+			},
+
+			TestStruct: &struct {
+				Field1 string
+				Field2 string
+				Nested struct {
+					Field3 string
+				} `tag:"nested"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string
+				Field2 string
+				Nested struct {
+					Field3 string
+				} `tag:"nested"`
+			}{},
+		},
+		{
+			It: "errors when two fields are fetched from the same env",
+
+			Prefix:        "prefix",
+			TagName:       "tag",
+			OverrideName:  "override",
+			IgnoreTagName: "ignore",
+
+			TestStruct: &struct {
+				Field1 string `tag:"field1" override:"SHARED"`
+				Nested struct {
+					Field2 string `tag:"field2"`
+					Field3 string `tag:"field3" override:"OTHER,SHARED"`
+				} `tag:"nested"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"field1" override:"SHARED"`
+				Nested struct {
+					Field2 string `tag:"field2"`
+					Field3 string `tag:"field3" override:"OTHER,SHARED"`
+				} `tag:"nested"`
+			}{},
+
+			Error: "env SHARED is used by both fields Field1 and Nested.Field3",
+		},
+		{
+			It: "errors when flattening nested structs makes two fields share an env",
+
+			Prefix:        "prefix",
+			TagName:       "tag",
+			IgnoreTagName: "ignore",
+
+			TestStruct: &struct {
+				Field1 string `tag:"port"`
+				Nested struct {
+					Field2 string `tag:"port"`
+				} `tag:"nested" ignore:"true"`
+			}{},
+
+			ResultStruct: &struct {
+				Field1 string `tag:"port"`
+				Nested struct {
+					Field2 string `tag:"port"`
+				} `tag:"nested" ignore:"true"`
+			}{},
+
+			Error: "env PREFIX_PORT is used by both fields Field1 and Nested.Field2",
+		},
+	} {
+		s.Run(t.It, func() {
+			env := envstruct.Envstruct{
+				Prefix:         t.Prefix,
+				TagName:        t.TagName,
+				TagNames:       t.TagNames,
+				OverrideName:   t.OverrideName,
+				IgnoreTagName:  t.IgnoreTagName,
+				StripValue:     t.StripValue,
+				BestEffort:     t.BestEffort,
+				RequireAll:     t.RequireAll,
+				OnConflict:     t.OnConflict,
+				Overrides:      t.Overrides,
+				Interpolate:    t.Interpolate,
+				NormalizeNames: t.Normalize,
+				DefaultTagName: t.DefaultTag,
+
+				Parser: envstruct.Parser{Delimiter: t.Delimiter, Unmarshaler: yaml.Unmarshal, Location: t.Location},
+			}
+
+			for name, value := range t.EnvValues {
+				os.Setenv(name, fmt.Sprintf("%v", value))
+			}
+
+			err := env.FetchEnv(t.TestStruct)
+			if t.Error != "" {
+				s.EqualError(err, t.Error)
+			} else {
+				s.NoError(err)
+			}
+
+			assert.Equal(s.T(), t.TestStruct, t.ResultStruct, "the struct should have correct env values populated")
+
+			os.Clearenv()
+		})
+	}
+}
+
+func (s *EnvstructSuite) TestConcurrentFetchEnv() {
+	type Config struct {
+		Field1 string         `tag:"field1"`
+		Field2 []int          `tag:"field2"`
+		Field3 map[string]int `tag:"field3"`
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "prefix",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	os.Setenv("PREFIX_FIELD1", "value")
+	os.Setenv("PREFIX_FIELD2", "1,2")
+	os.Setenv("PREFIX_FIELD3", "a:1")
+	defer os.Clearenv()
+
+	// Share the same Envstruct across goroutines, each populating their own
+	// struct. Run with -race to detect any shared state.
+	configs := make([]Config, 20)
+	errs := make([]error, len(configs))
+
+	var wg sync.WaitGroup
+	for i := range configs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = env.FetchEnv(&configs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range configs {
+		s.NoError(errs[i])
+		s.Equal(Config{
+			Field1: "value",
+			Field2: []int{1, 2},
+			Field3: map[string]int{"a": 1},
+		}, configs[i])
+	}
+}
+
+func (s *EnvstructSuite) TestFieldError() {
+	type Config struct {
+		Server struct {
+			Port int `tag:"port"`
+		} `tag:"server"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "prefix",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: envstruct.Unmarshal},
+	}
+
+	os.Setenv("PREFIX_SERVER_PORT", "abc")
+
+	var config Config
+	err := env.FetchEnv(&config)
+	s.EqualError(err, `field Server.Port (env PREFIX_SERVER_PORT): strconv.ParseInt: parsing "abc": invalid syntax`)
+
+	var fieldErr *envstruct.FieldError
+	s.True(errors.As(err, &fieldErr))
+	s.Equal("Server.Port", fieldErr.Path)
+	s.Equal("PREFIX_SERVER_PORT", fieldErr.Env)
+	s.Equal(envstruct.SourceEnv, fieldErr.Source)
 }
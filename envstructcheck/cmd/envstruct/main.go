@@ -0,0 +1,45 @@
+// Command envstruct provides tooling for configuration structs that are
+// fetched with envstruct.
+//
+//	envstruct lint [flags] ./...
+//
+// The lint command reports problems with struct tags, fields without
+// descriptions and inconsistently named env variables. Pass -help after lint
+// to see the flags of each check.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/clarafu/envstruct/envstructcheck"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+const usage = `usage: envstruct <command> [arguments]
+
+commands:
+  lint    report problems with the config structs of the given packages
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "lint":
+		// The multichecker parses the flags and packages from os.Args, so the
+		// command is removed before handing over to it
+		os.Args = append([]string{os.Args[0] + " lint"}, os.Args[2:]...)
+		multichecker.Main(envstructcheck.Analyzer, envstructcheck.LintAnalyzer)
+
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+
+	default:
+		fmt.Fprintf(os.Stderr, "envstruct: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,13 @@
+// Command envstructcheck checks the struct tags used by envstruct.
+//
+//	envstructcheck -tag env ./...
+package main
+
+import (
+	"github.com/clarafu/envstruct/envstructcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(envstructcheck.Analyzer)
+}
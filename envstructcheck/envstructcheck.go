@@ -0,0 +1,284 @@
+// Package envstructcheck defines an Analyzer that checks the struct tags used
+// by envstruct, so that mistakes are caught in CI rather than at runtime.
+//
+// It checks every struct that has at least one field with the configured tag
+// for:
+//
+//   - options after a comma that envstruct does not recognise
+//   - empty env names and empty aliases on fields that are fetched
+//   - two fields within the same struct using the same env name
+//   - ignore tag values that are not booleans
+//   - field types that envstruct does not support
+//   - tags on unexported fields, which envstruct skips
+package envstructcheck
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/clarafu/envstruct"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "envstructcheck",
+	Doc:      "check the struct tags used by envstruct",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var (
+	tagName       string
+	ignoreTagName string
+	stripValue    bool
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&tagName, "tag", "env", "the TagName that envstruct is configured with")
+	Analyzer.Flags.StringVar(&ignoreTagName, "ignore", "", "the IgnoreTagName that envstruct is configured with")
+	Analyzer.Flags.BoolVar(&stripValue, "strip", false, "whether envstruct is configured with StripValue")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		checkStruct(pass, n.(*ast.StructType))
+	})
+
+	return nil, nil
+}
+
+func checkStruct(pass *analysis.Pass, s *ast.StructType) {
+	// The env names used by the fields of the struct so far, to find duplicates
+	usedBy := map[string]string{}
+
+	for _, f := range s.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+
+		tagLiteral, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+
+		tag := reflect.StructTag(tagLiteral)
+
+		value, found := tag.Lookup(tagName)
+		if !found {
+			continue
+		}
+
+		// Fields tagged with "-" are excluded entirely
+		if value == "-" {
+			continue
+		}
+
+		fieldName := fieldName(f)
+
+		// Unexported fields are skipped, as they cannot be set
+		if len(f.Names) > 0 && !f.Names[0].IsExported() {
+			pass.Reportf(f.Tag.Pos(), "%s is unexported, so its %s tag is ignored", fieldName, tagName)
+			continue
+		}
+
+		values := strings.Split(value, ",")
+		name := values[0]
+
+		blob, jsonValue, required, optional := false, false, false, false
+		for _, option := range values[1:] {
+			switch strings.TrimSpace(option) {
+			case "blob":
+				blob = true
+			case "json":
+				jsonValue = true
+			case "required":
+				required = true
+			case "optional":
+				optional = true
+			}
+		}
+
+		if required && optional {
+			pass.Reportf(f.Tag.Pos(), "%s tag of %s has both the required and optional options", tagName, fieldName)
+		}
+
+		if !stripValue {
+			for _, option := range values[1:] {
+				if !envstruct.IsTagOption(option) {
+					pass.Reportf(f.Tag.Pos(), "unknown option %q in %s tag of %s, it will become part of the env name", strings.TrimSpace(option), tagName, fieldName)
+				}
+			}
+		}
+
+		ignored := false
+		if ignoreTagName != "" {
+			if ignore, found := tag.Lookup(ignoreTagName); found {
+				ignoreBool, err := strconv.ParseBool(ignore)
+				if err != nil {
+					pass.Reportf(f.Tag.Pos(), "%s tag of %s is %q, which is not a boolean", ignoreTagName, fieldName, ignore)
+				}
+
+				ignored = ignoreBool
+			}
+		}
+
+		fieldType := pass.TypesInfo.TypeOf(f.Type)
+		if fieldType == nil {
+			continue
+		}
+
+		// Nested structs can have empty tag values, as they only add to the env
+		// names of the fields within them, unless they are fetched from a single
+		// env through the blob or json options
+		if isNested(fieldType) && !blob && !jsonValue {
+			continue
+		}
+
+		// Values parsed as JSON are not split, so they can be nested
+		if reason := unsupported(fieldType); reason != "" && !(jsonValue && reason == nestedCollections) {
+			pass.Reportf(f.Type.Pos(), "%s has type %s, %s", fieldName, fieldType, reason)
+		}
+
+		if ignored {
+			continue
+		}
+
+		if name == "" {
+			pass.Reportf(f.Tag.Pos(), "%s tag of %s has an empty env name", tagName, fieldName)
+			continue
+		}
+
+		for _, alias := range strings.Split(name, "|") {
+			alias = strings.ToUpper(strings.TrimSpace(alias))
+			if alias == "" {
+				pass.Reportf(f.Tag.Pos(), "%s tag of %s has an empty alias", tagName, fieldName)
+				continue
+			}
+
+			if other, found := usedBy[alias]; found {
+				pass.Reportf(f.Tag.Pos(), "%s uses the env name %s, which is already used by %s", fieldName, alias, other)
+				continue
+			}
+
+			usedBy[alias] = fieldName
+		}
+	}
+}
+
+// fieldName returns the name of the field, or the name of the type for
+// embedded fields
+func fieldName(f *ast.Field) string {
+	if len(f.Names) > 0 {
+		return f.Names[0].Name
+	}
+
+	return types.ExprString(f.Type)
+}
+
+// isNested returns true if envstruct will walk through the fields of the type
+// rather than fetching it as a single value
+func isNested(t types.Type) bool {
+	if pointer, ok := t.Underlying().(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+
+	if isTime(t) || isOptional(t) || isTextUnmarshaler(t) {
+		return false
+	}
+
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+// isTextUnmarshaler returns true if a pointer to the type has an
+// UnmarshalText method, in which case it is fetched as a single value
+func isTextUnmarshaler(t types.Type) bool {
+	return types.NewMethodSet(types.NewPointer(t)).Lookup(nil, "UnmarshalText") != nil
+}
+
+// isOptional returns true if the type is an envstruct.Optional, which is
+// fetched as the type of its value
+func isOptional(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	return named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "github.com/clarafu/envstruct" && named.Obj().Name() == "Optional"
+}
+
+func isTime(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	return named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time"
+}
+
+// nestedCollections is the reason that slices and maps nested more than one
+// level deep are reported
+const nestedCollections = "slices and maps nested more than one level deep are not supported"
+
+// unsupported returns the reason why envstruct cannot fetch a value of the
+// type, or an empty string if it is supported
+func unsupported(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Chan, *types.Signature:
+		return "which cannot be fetched from an env"
+
+	case *types.Basic:
+		if u.Kind() == types.UnsafePointer {
+			return "which cannot be fetched from an env"
+		}
+
+	case *types.Pointer:
+		return unsupported(u.Elem())
+
+	case *types.Struct:
+		if isOptional(t) {
+			return unsupported(t.(*types.Named).TypeArgs().At(0))
+		}
+
+	case *types.Slice:
+		if isNestedCollection(u.Elem()) {
+			return nestedCollections
+		}
+
+	case *types.Map:
+		if isNestedCollection(u.Elem()) {
+			return nestedCollections
+		}
+	}
+
+	return ""
+}
+
+func isCollection(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return true
+	}
+
+	return false
+}
+
+// isNestedCollection returns true if the type is a slice or map whose items
+// are slices or maps themselves, which can not be nested any further
+func isNestedCollection(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		return isCollection(u.Elem())
+	case *types.Map:
+		return isCollection(u.Elem())
+	}
+
+	return false
+}
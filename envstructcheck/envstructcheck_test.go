@@ -0,0 +1,26 @@
+package envstructcheck_test
+
+import (
+	"testing"
+
+	"github.com/clarafu/envstruct/envstructcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	err := envstructcheck.Analyzer.Flags.Set("ignore", "ignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), envstructcheck.Analyzer, "a")
+}
+
+func TestLintAnalyzer(t *testing.T) {
+	err := envstructcheck.LintAnalyzer.Flags.Set("ignore", "ignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), envstructcheck.LintAnalyzer, "lint")
+}
@@ -0,0 +1,16 @@
+module github.com/clarafu/envstruct/envstructcheck
+
+go 1.22.0
+
+replace github.com/clarafu/envstruct => ../
+
+require (
+	github.com/clarafu/envstruct v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.30.0
+)
+
+require (
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	gopkg.in/yaml.v2 v2.3.0 // indirect
+)
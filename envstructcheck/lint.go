@@ -0,0 +1,155 @@
+package envstructcheck
+
+import (
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// LintAnalyzer goes further than Analyzer and reports problems that do not
+// break fetching but make the configuration harder to work with, such as
+// fields without descriptions and env names that do not follow the naming
+// used by the rest of the struct. It is run by the lint command alongside
+// Analyzer.
+var LintAnalyzer = &analysis.Analyzer{
+	Name:     "envstructlint",
+	Doc:      "check the config structs used by envstruct for missing descriptions and inconsistent naming",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runLint,
+}
+
+var (
+	lintTagName       string
+	lintIgnoreTagName string
+	descTagName       string
+)
+
+func init() {
+	LintAnalyzer.Flags.StringVar(&lintTagName, "tag", "env", "the TagName that envstruct is configured with")
+	LintAnalyzer.Flags.StringVar(&lintIgnoreTagName, "ignore", "", "the IgnoreTagName that envstruct is configured with")
+	LintAnalyzer.Flags.StringVar(&descTagName, "desc", "desc", "the tag that holds the description of each field, empty to not require descriptions")
+}
+
+func runLint(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		lintStruct(pass, n.(*ast.StructType))
+	})
+
+	return nil, nil
+}
+
+func lintStruct(pass *analysis.Pass, s *ast.StructType) {
+	// The casing of the first env name within the struct, which the rest of
+	// the names are expected to follow
+	var convention nameCase
+
+	for _, f := range s.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+
+		tagLiteral, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+
+		tag := reflect.StructTag(tagLiteral)
+
+		value, found := tag.Lookup(lintTagName)
+		if !found {
+			continue
+		}
+
+		if lintIgnoreTagName != "" {
+			if ignore, err := strconv.ParseBool(tag.Get(lintIgnoreTagName)); err == nil && ignore {
+				continue
+			}
+		}
+
+		fieldName := fieldName(f)
+		name := strings.Split(value, ",")[0]
+
+		for _, alias := range strings.Split(name, "|") {
+			alias = strings.TrimSpace(alias)
+			if alias == "" {
+				continue
+			}
+
+			if !isEnvName(alias) {
+				pass.Reportf(f.Tag.Pos(), "env name %q of %s contains characters that are not valid in a shell variable name", alias, fieldName)
+				continue
+			}
+
+			aliasCase := caseOf(alias)
+			if aliasCase == mixedCase {
+				pass.Reportf(f.Tag.Pos(), "env name %q of %s mixes upper and lower case, it will be fetched as %s", alias, fieldName, strings.ToUpper(alias))
+				continue
+			}
+
+			if convention == noCase {
+				convention = aliasCase
+			} else if aliasCase != noCase && aliasCase != convention {
+				pass.Reportf(f.Tag.Pos(), "env name %q of %s is %s while the rest of the struct is %s", alias, fieldName, aliasCase, convention)
+			}
+		}
+
+		fieldType := pass.TypesInfo.TypeOf(f.Type)
+		if fieldType == nil || isNested(fieldType) {
+			continue
+		}
+
+		if descTagName != "" && strings.TrimSpace(tag.Get(descTagName)) == "" {
+			pass.Reportf(f.Pos(), "%s has no %s tag describing it", fieldName, descTagName)
+		}
+	}
+}
+
+type nameCase string
+
+const (
+	noCase    nameCase = ""
+	lowerCase nameCase = "lower case"
+	upperCase nameCase = "upper case"
+	mixedCase nameCase = "mixed case"
+)
+
+// caseOf returns the casing of the letters within the name, names without
+// any letters have no case
+func caseOf(name string) nameCase {
+	var lower, upper bool
+	for _, r := range name {
+		lower = lower || unicode.IsLower(r)
+		upper = upper || unicode.IsUpper(r)
+	}
+
+	switch {
+	case lower && upper:
+		return mixedCase
+	case lower:
+		return lowerCase
+	case upper:
+		return upperCase
+	}
+
+	return noCase
+}
+
+// isEnvName returns true if the name only contains characters that are valid
+// within a shell variable name
+func isEnvName(name string) bool {
+	for _, r := range name {
+		if r != '_' && (r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,60 @@
+package a
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+type Config struct {
+	Host     string        `env:"host"`
+	Port     int           `env:"port,optional"`
+	Timeout  time.Duration `env:"timeout,omitempty"` // want `unknown option "omitempty" in env tag of Timeout, it will become part of the env name`
+	Empty    string        `env:""`                  // want `env tag of Empty has an empty env name`
+	Alias    string        `env:"addr||address"`     // want `env tag of Alias has an empty alias`
+	Hostname string        `env:"HOST"`              // want `Hostname uses the env name HOST, which is already used by Host`
+	Started  time.Time     `env:"started"`
+	Events   chan string   `env:"events"` // want `Events has type chan string, which cannot be fetched from an env`
+	Matrix   [][]int       `env:"matrix"`
+	Cube     [][][]int     `env:"cube"` // want `Cube has type \[\]\[\]\[\]int, slices and maps nested more than one level deep are not supported`
+	Database struct {
+		Port int `env:"port"`
+	} `env:""`
+	Ignored struct {
+		Host string `env:"host"`
+	} `env:"ignored" ignore:"maybe"` // want `ignore tag of Ignored is "maybe", which is not a boolean`
+	Limits struct {
+		Requests int `json:"requests"`
+	} `env:"limits,blob"`
+	EmptyBlob struct {
+		Requests int `json:"requests"`
+	} `env:",blob"` // want `env tag of EmptyBlob has an empty env name`
+	Retries  envstruct.Optional[int]      `env:"retries"`
+	Channel  envstruct.Optional[chan int] `env:"channel"` // want `Channel has type github.com/clarafu/envstruct.Optional\[chan int\], which cannot be fetched from an env`
+	NoName   envstruct.Optional[string]   `env:""`        // want `env tag of NoName has an empty env name`
+	Password string                       `env:"password,required"`
+	Both     string                       `env:"both,required,optional"` // want `env tag of Both has both the required and optional options`
+	Addr     Addr                         `env:""`                       // want `env tag of Addr has an empty env name`
+	Routes   map[string][]string          `env:"routes,json"`
+	Servers  []struct {
+		Host string `json:"host"`
+	} `env:"servers,json"`
+	Upstream struct {
+		Host string `json:"host"`
+	} `env:",json"` // want `env tag of Upstream has an empty env name`
+	Paths   []string          `env:"paths,delim=:"`
+	Links   map[string]string `env:"links,sep=="`
+	Inlined struct {
+		Level string `env:"level"`
+	} `env:",inline"`
+	hidden   string   `env:"hidden"` // want `hidden is unexported, so its env tag is ignored`
+	Client   chan int `env:"-"`
+	Mutex    chan int `env:"-"`
+	Untagged string
+}
+
+type Addr struct {
+	ip [4]byte
+}
+
+func (a *Addr) UnmarshalText(text []byte) error { return nil }
@@ -0,0 +1,9 @@
+// Package envstruct is a stub of the types of envstruct that the analyzer
+// recognizes
+package envstruct
+
+type Optional[T any] struct {
+	Value   T
+	Raw     string
+	Present bool
+}
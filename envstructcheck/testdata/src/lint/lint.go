@@ -0,0 +1,13 @@
+package lint
+
+type Config struct {
+	Host     string `env:"host" desc:"The host to listen on"`
+	Port     int    `env:"port"`                                 // want `Port has no desc tag describing it`
+	LogLevel string `env:"LOG_LEVEL" desc:"The level to log at"` // want `env name "LOG_LEVEL" of LogLevel is upper case while the rest of the struct is lower case`
+	DBHost   string `env:"dbHost" desc:"The database host"`      // want `env name "dbHost" of DBHost mixes upper and lower case, it will be fetched as DBHOST`
+	Region   string `env:"aws-region" desc:"The AWS region"`     // want `env name "aws-region" of Region contains characters that are not valid in a shell variable name`
+	Skipped  string `env:"SKIPPED" ignore:"true"`
+	Database struct {
+		User string `env:"user" desc:"The database user"`
+	} `env:"db"`
+}
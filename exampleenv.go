@@ -0,0 +1,86 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// WriteExampleEnv writes an example .env file for the struct to the writer,
+// with a line for every env that it is fetched from, so that new developers
+// can copy it rather than finding the envs within the source. Each env is set
+// to its default and preceded by a comment with its description, type and
+// whether it is required, for ex.
+//
+//	# listen port (int)
+//	APP_PORT=8080
+//
+//	# admin password (string, required, secret)
+//	APP_PASSWORD=
+//
+// The value already set on a field is used as its default, unless it has one
+// within the DefaultTagName. Envs without a default, and secrets, are left
+// empty, which is treated the same as the env not being set.
+func (e Envstruct) WriteExampleEnv(w io.Writer, object interface{}) error {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return errors.New("failed to write example env for object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return err
+	}
+
+	var example strings.Builder
+	for _, f := range fields {
+		if !f.tagged || len(f.envNames) == 0 {
+			continue
+		}
+
+		spec := e.fieldSpec(f)
+
+		notes := []string{typeName(f.description.Type)}
+		if spec.Required {
+			notes = append(notes, "required")
+		}
+
+		if spec.Secret {
+			notes = append(notes, "secret")
+		}
+
+		if example.Len() > 0 {
+			example.WriteString("\n")
+		}
+
+		comment := strings.TrimSpace(fmt.Sprintf("%s (%s)", spec.Description, strings.Join(notes, ", ")))
+		fmt.Fprintf(&example, "# %s\n", strings.ReplaceAll(comment, "\n", "\n# "))
+
+		var value string
+		if !spec.Secret {
+			value = dotenvQuote(spec.Default)
+		}
+
+		fmt.Fprintf(&example, "%s=%s\n", f.envNames[0], value)
+	}
+
+	_, err = io.WriteString(w, example.String())
+
+	return err
+}
+
+// dotenvQuoter escapes the characters that cannot be written as they are within
+// double quotes, the reverse of the dotenvEscapes
+var dotenvQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// dotenvQuote wraps the value in double quotes if it would not be read back
+// the same from a .env file otherwise, for ex. because it contains a comment
+// or surrounding spaces
+func dotenvQuote(value string) string {
+	if !strings.ContainsAny(value, "#\"'\\\n\r\t") && strings.TrimSpace(value) == value {
+		return value
+	}
+
+	return `"` + dotenvQuoter.Replace(value) + `"`
+}
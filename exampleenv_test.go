@@ -0,0 +1,69 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestWriteExampleEnv() {
+	type Config struct {
+		Port     int           `tag:"port" desc:"listen port"`
+		Host     string        `tag:"host|hostname,required" desc:"listen address"`
+		Timeout  time.Duration `tag:"timeout" default:"30s"`
+		Greeting string        `tag:"greeting"`
+		Password string        `tag:"password,secret" desc:"admin password"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:         "app",
+		TagName:        "tag",
+		DefaultTagName: "default",
+	}
+
+	config := Config{Port: 8080, Greeting: "hi # there", Password: "hunter2"}
+
+	s.Run("writes each env with its default", func() {
+		var example strings.Builder
+		err := env.WriteExampleEnv(&example, &config)
+		s.NoError(err)
+
+		s.Equal(`# listen port (int)
+APP_PORT=8080
+
+# listen address (string, required)
+APP_HOST=
+
+# (duration)
+APP_TIMEOUT=30s
+
+# (string)
+APP_GREETING="hi # there"
+
+# admin password (string, secret)
+APP_PASSWORD=
+`, example.String())
+	})
+
+	s.Run("can be read back as a dotenv file", func() {
+		var example strings.Builder
+		err := env.WriteExampleEnv(&example, &config)
+		s.NoError(err)
+
+		path := filepath.Join(s.T().TempDir(), ".env")
+		s.NoError(os.WriteFile(path, []byte(example.String()), 0600))
+
+		dotenv, err := envstruct.ReadDotenvFile(path)
+		s.NoError(err)
+
+		value, _ := dotenv.Lookup("APP_GREETING")
+		s.Equal("hi # there", value)
+
+		value, _ = dotenv.Lookup("APP_TIMEOUT")
+		s.Equal("30s", value)
+	})
+}
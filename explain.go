@@ -0,0 +1,82 @@
+package envstruct
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Binding describes every env that a single field of a struct could be
+// fetched from, as returned by Explain
+type Binding struct {
+	// Field is the path to the field within the struct, for ex. "Database.Host"
+	Field string `json:"field"`
+
+	// EnvNames are the names of the envs that the field is fetched from, in
+	// order of precedence. They are the names from the OverrideName tag if
+	// the field has one.
+	EnvNames []string `json:"env_names,omitempty"`
+
+	// DeprecatedNames are the names of the envs that the field used to be
+	// fetched from, which are tried after the EnvNames
+	DeprecatedNames []string `json:"deprecated_names,omitempty"`
+
+	// FileNames are the names of the envs that hold the path of a file to
+	// read the field from when none of its envs are set, if the FileSuffix is
+	// set
+	FileNames []string `json:"file_names,omitempty"`
+
+	// SourceNames are the names that the field is looked up under within the
+	// sources, keyed by the name of the source, from the SourceNameTags
+	SourceNames map[string]string `json:"source_names,omitempty"`
+
+	// Profiles are the only profiles that the field is resolved in, from the
+	// ProfilesTagName
+	Profiles []string `json:"profiles,omitempty"`
+
+	// Overridden is true if the field is pinned through the Overrides, in
+	// which case none of its envs are read
+	Overridden bool `json:"overridden,omitempty"`
+}
+
+// Explain returns a Binding for every field of the struct that can be fetched
+// from the env or is pinned through the Overrides, in the order that they are
+// declared, without reading any of the sources. As the profile is not read
+// either, the names of the envs scoped to a profile are not included, and
+// every field is returned regardless of the profiles it is resolved in.
+func (e Envstruct) Explain(object interface{}) ([]Binding, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to explain object, needs to be type struct")
+	}
+
+	fields, err := e.extractFields(object, "")
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := []Binding{}
+	for _, f := range fields {
+		_, overridden := e.Overrides[f.path]
+		if len(f.envNames) == 0 && len(f.sourceNames) == 0 && !overridden {
+			continue
+		}
+
+		binding := Binding{
+			Field:           f.path,
+			EnvNames:        f.envNames,
+			DeprecatedNames: f.deprecatedNames,
+			SourceNames:     f.sourceNames,
+			Profiles:        f.profiles,
+			Overridden:      overridden,
+		}
+
+		if e.FileSuffix != "" {
+			for _, envName := range f.envNames {
+				binding.FileNames = append(binding.FileNames, envName+e.FileSuffix)
+			}
+		}
+
+		bindings = append(bindings, binding)
+	}
+
+	return bindings, nil
+}
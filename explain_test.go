@@ -0,0 +1,63 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestExplain() {
+	type Config struct {
+		Host     string `tag:"host|hostname"`
+		Port     int    `tag:"port" override:"PORT"`
+		Password string `tag:"password" deprecated:"pass"`
+		Replicas int    `tag:"replicas" profiles:"prod"`
+		Debug    bool
+		Nested   struct {
+			Field string `tag:"field"`
+		} `tag:"nested"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:       "app",
+		TagName:      "tag",
+		OverrideName: "override",
+		FileSuffix:   "_FILE",
+		Overrides:    map[string]string{"Debug": "true"},
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("lists every env that each field could be fetched from", func() {
+		bindings, err := env.Explain(&Config{})
+		s.NoError(err)
+
+		s.Equal([]envstruct.Binding{
+			{Field: "Host", EnvNames: []string{"APP_HOST", "APP_HOSTNAME"}, FileNames: []string{"APP_HOST_FILE", "APP_HOSTNAME_FILE"}},
+			{Field: "Port", EnvNames: []string{"PORT"}, FileNames: []string{"PORT_FILE"}},
+			{Field: "Password", EnvNames: []string{"APP_PASSWORD"}, DeprecatedNames: []string{"APP_PASS"}, FileNames: []string{"APP_PASSWORD_FILE"}},
+			{Field: "Replicas", EnvNames: []string{"APP_REPLICAS"}, FileNames: []string{"APP_REPLICAS_FILE"}, Profiles: []string{"PROD"}},
+			{Field: "Debug", Overridden: true},
+			{Field: "Nested.Field", EnvNames: []string{"APP_NESTED_FIELD"}, FileNames: []string{"APP_NESTED_FIELD_FILE"}},
+		}, bindings)
+	})
+
+	s.Run("does not read the environment", func() {
+		os.Clearenv()
+		os.Setenv("APP_PROFILE", "unknown")
+
+		env := env
+		env.ProfileEnv = "APP_PROFILE"
+		env.Profiles = []string{"dev", "prod"}
+
+		bindings, err := env.Explain(&Config{})
+		s.NoError(err)
+		s.Len(bindings, 6)
+	})
+}
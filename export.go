@@ -0,0 +1,56 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExportScript renders the current configuration of the struct as a shell
+// script of `export NAME='value'` lines, so that the environment of a service
+// can be replicated locally with `eval`. Secrets are masked and commented out
+// so that they are not exported, unless withSecrets is true. Fields that are
+// nil pointers are left out.
+func (e Envstruct) ExportScript(object interface{}, withSecrets bool) (string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return "", errors.New("failed to export object, needs to be type struct")
+	}
+
+	values, err := e.envValues(object)
+	if err != nil {
+		return "", err
+	}
+
+	var script strings.Builder
+	for _, v := range values {
+		if v.field.options.secret && !withSecrets {
+			if v.field.value.IsZero() {
+				fmt.Fprintf(&script, "# export %s=''\n", v.name)
+			} else {
+				fmt.Fprintf(&script, "# export %s=%s\n", v.name, shellQuote(redacted))
+			}
+
+			continue
+		}
+
+		fmt.Fprintf(&script, "export %s=%s\n", v.name, shellQuote(v.value))
+	}
+
+	return script.String(), nil
+}
+
+// shellQuote wraps the value in single quotes, which stops the shell from
+// expanding anything within it
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// isPlainValue returns true if the value only contains characters that are
+// never interpreted within the files that envs are written to, so it does not
+// need to be quoted
+func isPlainValue(value string) bool {
+	return strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-.,:/@+=%", r))
+	}) == -1
+}
@@ -0,0 +1,58 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestExportScript() {
+	type Config struct {
+		Host     string            `tag:"host"`
+		Timeout  time.Duration     `tag:"timeout"`
+		Message  string            `tag:"message"`
+		Peers    []string          `tag:"peers"`
+		Labels   map[string]string `tag:"labels"`
+		Port     *int              `tag:"port"`
+		Password string            `tag:"password,secret"`
+		Token    string            `tag:"token,secret"`
+		Internal string
+	}
+
+	config := Config{
+		Host:     "localhost",
+		Timeout:  time.Minute,
+		Message:  "it's $HOME",
+		Peers:    []string{"a", "b"},
+		Labels:   map[string]string{"team": "infra", "env": "prod"},
+		Password: "hunter2",
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+		Parser:  envstruct.Parser{Delimiter: ";"},
+	}
+
+	s.Run("masks secrets", func() {
+		script, err := env.ExportScript(&config, false)
+		s.NoError(err)
+
+		s.Equal(`export APP_HOST='localhost'
+export APP_TIMEOUT='1m0s'
+export APP_MESSAGE='it'\''s $HOME'
+export APP_PEERS='a;b'
+export APP_LABELS='env:prod;team:infra'
+# export APP_PASSWORD='******'
+# export APP_TOKEN=''
+`, script)
+	})
+
+	s.Run("includes secrets when asked to", func() {
+		script, err := env.ExportScript(&config, true)
+		s.NoError(err)
+
+		s.Contains(script, "export APP_PASSWORD='hunter2'\n")
+		s.Contains(script, "export APP_TOKEN=''\n")
+	})
+}
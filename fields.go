@@ -0,0 +1,472 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// field is a field within the struct that can be fetched from the env
+type field struct {
+	// path is the path to the field within the struct, for ex. "Nested.Field"
+	path string
+
+	// index is the index sequence of the field within the struct, as used by
+	// reflect.Value.FieldByIndex
+	index []int
+
+	// envNames are the names of the envs that the field can be fetched from, in
+	// order of precedence
+	envNames []string
+
+	// tagged is true if the field has a tag matching the TagName
+	tagged bool
+
+	// options are the envstruct options set within the tag of the field
+	options tagOptions
+
+	// sources are the names of the only sources that the field can be fetched
+	// from, set through the SourceTagName
+	sources []string
+
+	// sourceNames are the names that the field is looked up under within each
+	// source, keyed by the name of the source, set through the SourceNameTags
+	sourceNames map[string]string
+
+	// profiles are the only profiles that the field is resolved in, set
+	// through the ProfilesTagName
+	profiles []string
+
+	// requiredInProfiles is true if the field is resolved in every profile but
+	// only required in its profiles
+	requiredInProfiles bool
+
+	// deprecatedNames are the names of the envs that the field used to be
+	// fetched from, set through the DeprecatedTagName. They are tried after
+	// the envNames.
+	deprecatedNames []string
+
+	// enum are the only values that the field can be set to, set through the
+	// EnumTagName
+	enum []string
+
+	// description is the description of the field within the struct
+	description reflect.StructField
+
+	// elements is set on a slice or map of structs that has been grown to fit
+	// every element set within the sources, or a nil pointer to a struct that
+	// has been allocated, and replaces it once the plan is applied. The fields
+	// of its elements are fields of their own.
+	elements reflect.Value
+
+	// entries are the elements of a grown map of structs, which are set on the
+	// map once the fields within them have been applied
+	entries []entry
+
+	// value is the value of the field within the struct
+	value reflect.Value
+}
+
+// fields will walk through the struct and return every field within it that
+// can be fetched from the env, along with the names of the envs that it can be
+// fetched from. Nested structs are walked through and their tags are used to
+// build up the names of the envs of the fields within them.
+func (e Envstruct) fields(object interface{}) ([]*field, error) {
+	// The envs scoped to the active profile are tried first, for ex.
+	// PREFIX_DEV_FIELD before PREFIX_FIELD
+	profile, err := e.activeProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := e.extractFields(object, profile)
+	fields = filterProfiles(fields, profile)
+	if err != nil && !e.BestEffort {
+		return fields, err
+	}
+
+	// Make sure that no two fields are fetched from the same env, which would
+	// otherwise silently set both fields from the same value
+	duplicateErr := checkDuplicates(fields)
+	if duplicateErr != nil {
+		if !e.BestEffort {
+			return fields, duplicateErr
+		}
+
+		return fields, Errors{}.collect(err).collect(duplicateErr).errorOrNil()
+	}
+
+	return fields, err
+}
+
+// extractFields will walk through the struct and return every field within it,
+// with the names of the envs scoped to the profile (if any) tried first. The
+// fields are not filtered by the profiles that they are resolved in.
+func (e Envstruct) extractFields(object interface{}, profile string) ([]*field, error) {
+	// Start building up the strings that will be used to fetch the env. They
+	// start with the prefix (if set) and can contain any nested struct tag
+	// values and field tag values. There is more than one string being built up
+	// when a tag value contains aliases, or when there are multiple prefixes.
+	var envNameBuilders [][]string
+	for _, envPrefix := range e.envPrefixes(object) {
+		envNameBuilders = append(envNameBuilders, []string{envPrefix})
+	}
+
+	if len(envNameBuilders) == 0 {
+		envNameBuilders = [][]string{nil}
+	}
+
+	if profile != "" {
+		scoped := make([][]string, len(envNameBuilders))
+		for i, envNameBuilder := range envNameBuilders {
+			scoped[i] = append(append([]string{}, envNameBuilder...), profile)
+		}
+
+		envNameBuilders = append(scoped, envNameBuilders...)
+	}
+
+	var fields []*field
+	err := e.extractStruct(&fields, envNameBuilders, nil, nil, reflect.ValueOf(object).Elem())
+
+	return fields, err
+}
+
+// envPrefixes returns the uppercased prefixes that the names of the envs start
+// with, in the order that they are tried
+func (e Envstruct) envPrefixes(object interface{}) []string {
+	// If no prefix has been configured, use the prefix that the struct declares
+	// for itself (if any)
+	prefix := e.Prefix
+	if prefixer, ok := object.(Prefixer); ok && prefix == "" {
+		prefix = prefixer.EnvPrefix()
+	}
+
+	var envPrefixes []string
+	for _, prefix := range append([]string{prefix}, e.Prefixes...) {
+		if envPrefix := e.normalizeName(strings.ToUpper(prefix)); envPrefix != "" {
+			envPrefixes = append(envPrefixes, envPrefix)
+		}
+	}
+
+	return envPrefixes
+}
+
+// checkDuplicates returns an error if any env name can be used to fetch more
+// than one field, for ex. through overrides or ignored tags.
+func checkDuplicates(fields []*field) error {
+	var errs Errors
+
+	usedBy := map[string]string{}
+	for _, f := range fields {
+		for _, envName := range append(f.envNames[:len(f.envNames):len(f.envNames)], f.deprecatedNames...) {
+			if path, found := usedBy[envName]; found && path != f.path {
+				errs = append(errs, fmt.Errorf("env %s is used by both fields %s and %s", envName, path, f.path))
+				continue
+			}
+
+			usedBy[envName] = f.path
+		}
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errs.errorOrNil()
+}
+
+func (e Envstruct) extractTag(fields *[]*field, envNameBuilders [][]string, path []string, index []int, fieldDescription reflect.StructField, fieldValue reflect.Value) error {
+	// Keep track of the path to the field within the struct so that the field
+	// can be identified in any errors
+	path = append(path[:len(path):len(path)], fieldDescription.Name)
+	index = append(index[:len(index):len(index)], fieldDescription.Index...)
+
+	// Unexported fields cannot be set, apart from the exported fields within
+	// embedded structs, so they are skipped unless RejectUnexported is on
+	if !fieldDescription.IsExported() && !(fieldDescription.Anonymous && fieldDescription.Type.Kind() == reflect.Struct) {
+		if _, found := e.lookupTag(fieldDescription.Tag); found && e.RejectUnexported {
+			return fmt.Errorf("field %s is unexported, so it cannot be set", strings.Join(path, "."))
+		}
+
+		return nil
+	}
+
+	// Fetch the tag value from the struct and append it to the string that will
+	// be used to fetch the env value
+	tagValue, found := e.lookupTag(fieldDescription.Tag)
+
+	// Fields without a tag have their env name derived from the name of the
+	// field when DeriveNames is on, apart from embedded structs which stay
+	// inlined into their parent
+	if !found && e.DeriveNames && !fieldDescription.Anonymous {
+		tagValue, found = SnakeCase(fieldDescription.Name), true
+	}
+
+	// A tag value of "-" excludes the field entirely, the same as for the
+	// encoding packages, so it is neither fetched nor walked through
+	if found && tagValue == "-" {
+		return nil
+	}
+
+	// Keep the strings built up to the parent struct, which the deprecated names
+	// of the field are built from
+	parentNameBuilders := envNameBuilders
+
+	var options tagOptions
+	if found {
+		tagValue, options = e.parseTagValue(tagValue)
+
+		includeTag := true
+
+		if e.IgnoreTagName != "" {
+			ignore, found := fieldDescription.Tag.Lookup(e.IgnoreTagName)
+
+			if found {
+				ignoreBool, err := strconv.ParseBool(ignore)
+				if err != nil {
+					return err
+				}
+
+				if ignoreBool {
+					includeTag = false
+				}
+			}
+		}
+
+		// Inlined structs add nothing onto the env names, the same as embedded
+		// structs without a tag
+		if includeTag && tagValue != "" && !options.inline {
+			envNameBuilders = appendAliases(envNameBuilders, strings.Split(e.normalizeName(strings.ToUpper(tagValue)), "|"))
+		}
+	}
+
+	// If the field is a struct then loop through each field and recurse, unless
+	// the whole struct is fetched from a single env through the blob or json
+	// options
+	if fieldDescription.Type.Kind() == reflect.Struct && !isValueStruct(fieldDescription.Type) && !options.single() {
+		return e.extractStruct(fields, envNameBuilders, path, index, fieldValue)
+	} else if isStructSlice(fieldDescription.Type) && found && !options.single() {
+		// Each element of a tagged slice of structs is fetched from indexed envs,
+		// for ex. PREFIX_UPSTREAMS_0_HOST
+		return e.extractSlice(fields, envNameBuilders, path, index, fieldValue)
+	} else if isStructMap(fieldDescription.Type) && found && !options.single() {
+		// Each element of a tagged map of structs is fetched from envs holding
+		// its key, for ex. PREFIX_DATABASES_USERS_HOST
+		return e.extractMap(fields, envNameBuilders, path, index, fieldValue)
+	} else if fieldDescription.Type.Kind() == reflect.Ptr && fieldDescription.Type.Elem().Kind() == reflect.Struct && !isValueStruct(fieldDescription.Type.Elem()) && !options.single() {
+		return e.extractPointer(fields, envNameBuilders, path, index, fieldValue)
+	} else {
+		// If the field is not a struct, the env is fetched using the built up
+		// strings. Fields without a tag are not fetched from the env, although
+		// they can still be set through the Overrides.
+		var envNames, deprecatedNames []string
+		if found {
+			envNames = e.buildNames(envNameBuilders, fieldDescription)
+			deprecatedNames = e.deprecatedNames(parentNameBuilders, fieldDescription)
+		}
+
+		// If there is an override tag set, try to see if this field has the
+		// override set. If it does then use that value to fetch the env with
+		if e.OverrideName != "" {
+			if override, found := fieldDescription.Tag.Lookup(e.OverrideName); found {
+				envNames = strings.Split(override, ",")
+				for i, envName := range envNames {
+					envNames[i] = e.normalizeName(strings.TrimSpace(envName))
+				}
+			}
+		}
+
+		// If there is a source tag set, the field can only be fetched from the
+		// sources that it lists
+		var sources []string
+		if e.SourceTagName != "" {
+			if sourceTag, found := fieldDescription.Tag.Lookup(e.SourceTagName); found {
+				for _, source := range strings.Split(sourceTag, ",") {
+					if source = strings.TrimSpace(source); source != "" {
+						sources = append(sources, source)
+					}
+				}
+			}
+		}
+
+		// If there are tags for the names within sources, the field is looked up
+		// under those names within them
+		var sourceNames map[string]string
+		for _, source := range e.SourceNameTags {
+			if name, found := fieldDescription.Tag.Lookup(source); found {
+				if sourceNames == nil {
+					sourceNames = map[string]string{}
+				}
+
+				sourceNames[source] = strings.TrimSpace(name)
+			}
+		}
+
+		profiles, requiredInProfiles := e.fieldProfiles(fieldDescription.Tag)
+
+		*fields = append(*fields, &field{
+			path:               strings.Join(path, "."),
+			index:              index,
+			envNames:           envNames,
+			tagged:             found,
+			options:            options,
+			sources:            sources,
+			sourceNames:        sourceNames,
+			profiles:           profiles,
+			requiredInProfiles: requiredInProfiles,
+			deprecatedNames:    deprecatedNames,
+			enum:               e.fieldEnum(fieldDescription.Tag),
+			description:        fieldDescription,
+			value:              fieldValue,
+		})
+	}
+
+	return nil
+}
+
+// buildNames builds the names of the envs of the field from each of the
+// strings that have been built up, using the NameFunc if it is set
+func (e Envstruct) buildNames(envNameBuilders [][]string, fieldDescription reflect.StructField) []string {
+	var envNames []string
+	for _, envNameBuilder := range envNameBuilders {
+		if e.NameFunc != nil {
+			envNames = append(envNames, e.NameFunc(append([]string{}, envNameBuilder...), fieldDescription)...)
+			continue
+		}
+
+		envNames = append(envNames, e.buildName(envNameBuilder))
+	}
+
+	return envNames
+}
+
+// buildName builds the name of an env from its segments the same way as
+// BuildName, joining them with the NameSeparator instead
+func (e Envstruct) buildName(segments []string) string {
+	return joinName(segments, e.nameSeparator())
+}
+
+// nameSeparator returns the NameSeparator, which is defaulted to an underscore
+func (e Envstruct) nameSeparator() string {
+	if e.NameSeparator != "" {
+		return e.NameSeparator
+	}
+
+	return "_"
+}
+
+// BuildName builds the name of an env from its segments, which are the prefix
+// followed by the tag values of each nested struct and the field. Each segment
+// is trimmed and uppercased, and the segments are joined with an underscore,
+// for ex. ["prefix", "db", "host"] builds "PREFIX_DB_HOST".
+func BuildName(segments []string) string {
+	return joinName(segments, "_")
+}
+
+// joinName trims and uppercases each segment and joins them with the
+// separator
+func joinName(segments []string, separator string) string {
+	trimmed := make([]string, len(segments))
+	for i, segment := range segments {
+		trimmed[i] = strings.TrimSpace(segment)
+	}
+
+	return strings.ToUpper(strings.Join(trimmed, separator))
+}
+
+// lookupTag will return the value of the first tag on the field that matches
+// the TagName or one of the TagNames.
+func (e Envstruct) lookupTag(tag reflect.StructTag) (string, bool) {
+	if e.TagName != "" {
+		if value, found := tag.Lookup(e.TagName); found {
+			return value, true
+		}
+	}
+
+	for _, tagName := range e.TagNames {
+		if value, found := tag.Lookup(tagName); found {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// appendAliases will append the tag value onto each of the env names being
+// built up. A tag value can contain multiple aliases separated by a "|", for
+// ex. `env:"addr|address"`, in which case each env name is branched off into
+// one env name per alias. The aliases are kept in order so that they are tried
+// in the order that they are listed.
+func appendAliases(envNameBuilders [][]string, aliases []string) [][]string {
+	var appended [][]string
+	for _, envNameBuilder := range envNameBuilders {
+		for _, alias := range aliases {
+			builder := make([]string, len(envNameBuilder), len(envNameBuilder)+1)
+			copy(builder, envNameBuilder)
+
+			appended = append(appended, append(builder, strings.TrimSpace(alias)))
+		}
+	}
+
+	return appended
+}
+
+// extractPointer will extract the tags of each field within the struct that
+// the pointer points to. When fetching, a nil pointer is allocated if any of
+// the envs of the fields within the struct are set, in which case a field is
+// added for the pointer itself so that it is set once the plan is applied.
+// Otherwise the pointer is left nil, so that optional sections of the
+// configuration can stay nil.
+func (e Envstruct) extractPointer(fields *[]*field, envNameBuilders [][]string, path []string, index []int, pointerValue reflect.Value) error {
+	if !pointerValue.IsNil() {
+		return e.extractStruct(fields, envNameBuilders, path, index, pointerValue.Elem())
+	}
+
+	// Pointers that cannot be set, such as embedded pointers to unexported
+	// structs, cannot be allocated
+	if !e.growCollections || !pointerValue.CanSet() {
+		return nil
+	}
+
+	allocated := reflect.New(pointerValue.Type().Elem())
+
+	var allocatedFields []*field
+	err := e.extractStruct(&allocatedFields, envNameBuilders, path, index, allocated.Elem())
+	if err != nil {
+		return err
+	}
+
+	found, err := e.anySet(allocatedFields)
+	if err != nil || !found {
+		return err
+	}
+
+	*fields = append(*fields, &field{
+		path:     strings.Join(path, "."),
+		index:    index,
+		elements: allocated,
+		value:    pointerValue,
+	})
+	*fields = append(*fields, allocatedFields...)
+
+	return nil
+}
+
+// extractStruct will extract the tags of each field within the nested struct.
+// In best effort mode, every field is visited even if some of them fail.
+func (e Envstruct) extractStruct(fields *[]*field, envNameBuilders [][]string, path []string, index []int, structValue reflect.Value) error {
+	var errs Errors
+	for i := 0; i < structValue.NumField(); i++ {
+		err := e.extractTag(fields, envNameBuilders, path, index, structValue.Type().Field(i), structValue.Field(i))
+		if err != nil {
+			if !e.BestEffort {
+				return err
+			}
+
+			errs = errs.collect(err)
+		}
+	}
+
+	return errs.errorOrNil()
+}
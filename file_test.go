@@ -0,0 +1,56 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestFileOption() {
+	type Config struct {
+		TLS struct {
+			Cert string `tag:"cert,file"`
+			Key  []byte `tag:"key,file,secret"`
+		} `tag:"tls"`
+		Workers int `tag:"workers,file"`
+	}
+
+	defer os.Clearenv()
+
+	dir := s.T().TempDir()
+	cert := filepath.Join(dir, "tls.crt")
+	s.NoError(os.WriteFile(cert, []byte("-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n"), 0600))
+	workers := filepath.Join(dir, "workers")
+	s.NoError(os.WriteFile(workers, []byte("4\n"), 0600))
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("reads the file at the path set in the env", func() {
+		os.Setenv("APP_TLS_CERT", cert)
+		os.Setenv("APP_WORKERS", workers)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n", config.TLS.Cert)
+		s.Empty(config.TLS.Key)
+		s.Equal(4, config.Workers)
+	})
+
+	s.Run("fails when the file can not be read", func() {
+		missing := filepath.Join(dir, "tls.key")
+		os.Setenv("APP_TLS_KEY", missing)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field TLS.Key (env APP_TLS_KEY): open "+missing+": no such file or directory")
+	})
+}
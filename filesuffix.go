@@ -0,0 +1,42 @@
+package envstruct
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// lookupFileSuffix will look up each of the env names of the field with the
+// FileSuffix appended, and read the file at the path that the first one set
+// points to. The name of the env and the source it was found in are returned
+// along with the contents of the file, or empty strings if none are set. A
+// single trailing newline is removed from the contents, as most secrets are
+// written with one.
+func (e Envstruct) lookupFileSuffix(f *field) (string, string, string, error) {
+	if e.FileSuffix == "" {
+		return "", "", "", nil
+	}
+
+	for _, envName := range f.envNames {
+		name := envName + e.FileSuffix
+
+		path, source, err := e.lookup(name, f.sources, nil)
+		if err != nil {
+			return "", "", "", err
+		}
+
+		if path == "" {
+			continue
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed to read file set in %s: %w", name, err)
+		}
+
+		value := strings.TrimSuffix(string(contents), "\n")
+		return strings.TrimSuffix(value, "\r"), name, source, nil
+	}
+
+	return "", "", "", nil
+}
@@ -0,0 +1,85 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestFileSuffix() {
+	type Config struct {
+		Database struct {
+			User     string `tag:"user"`
+			Password string `tag:"password,secret"`
+		} `tag:"db"`
+		Port int `tag:"port|listen_port"`
+	}
+
+	defer os.Clearenv()
+
+	dir := s.T().TempDir()
+	password := filepath.Join(dir, "password")
+	s.NoError(os.WriteFile(password, []byte("hunter2\n"), 0600))
+	port := filepath.Join(dir, "port")
+	s.NoError(os.WriteFile(port, []byte("8080"), 0600))
+
+	env := envstruct.Envstruct{
+		Prefix:     "app",
+		TagName:    "tag",
+		FileSuffix: "_FILE",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("reads the value from the file the env with the suffix points to", func() {
+		os.Clearenv()
+		os.Setenv("APP_DB_USER", "admin")
+		os.Setenv("APP_DB_PASSWORD_FILE", password)
+		os.Setenv("APP_LISTEN_PORT_FILE", port)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("admin", config.Database.User)
+		s.Equal("hunter2", config.Database.Password)
+		s.Equal(8080, config.Port)
+	})
+
+	s.Run("prefers the env over the file", func() {
+		os.Clearenv()
+		os.Setenv("APP_DB_PASSWORD", "from env")
+		os.Setenv("APP_DB_PASSWORD_FILE", password)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("from env", config.Database.Password)
+	})
+
+	s.Run("fails when the file can not be read", func() {
+		os.Clearenv()
+		os.Setenv("APP_DB_PASSWORD_FILE", filepath.Join(dir, "missing"))
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "failed to read file set in APP_DB_PASSWORD_FILE: open "+filepath.Join(dir, "missing")+": no such file or directory")
+	})
+
+	s.Run("ignores the suffix when the FileSuffix is not set", func() {
+		os.Clearenv()
+		os.Setenv("APP_DB_PASSWORD_FILE", password)
+
+		env := env
+		env.FileSuffix = ""
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Empty(config.Database.Password)
+	})
+}
@@ -0,0 +1,66 @@
+package envstruct
+
+import "sync"
+
+// fileValues holds the values that one of the file sources read from its
+// file, so that they can be read again when the file changes
+type fileValues struct {
+	path string
+	read func(path string) (map[string]string, error)
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+func newFileValues(path string, read func(path string) (map[string]string, error)) *fileValues {
+	return &fileValues{
+		path:   path,
+		read:   read,
+		values: map[string]string{},
+	}
+}
+
+// Files returns the path of the file, if any
+func (f *fileValues) Files() []string {
+	if f.path == "" {
+		return nil
+	}
+
+	return []string{f.path}
+}
+
+// Reload reads the file again. If it fails, the values read before are kept.
+func (f *fileValues) Reload() error {
+	if f.path == "" {
+		return nil
+	}
+
+	values, err := f.read(f.path)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values = values
+
+	return nil
+}
+
+// Lookup returns the value of the env within the file
+func (f *fileValues) Lookup(envName string) (string, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	value, found := f.values[envName]
+	return value, found
+}
+
+// Names returns the name of every env within the file
+func (f *fileValues) Names() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return mapNames(f.values)
+}
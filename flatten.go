@@ -0,0 +1,78 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// flatten will add each of the values of the decoded JSON object onto the
+// flattened values, named after the prefix and their key. Nested objects are
+// recursed into with their key joined onto the prefix by an underscore, for
+// ex. {"db": {"host": "localhost"}} is flattened to DB_HOST.
+func flatten(flattened map[string]string, prefix string, values map[string]interface{}) {
+	for key, value := range values {
+		name := flatName(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch nested := value.(type) {
+		case map[string]interface{}:
+			flatten(flattened, name, nested)
+			continue
+		case map[interface{}]interface{}:
+			// Nested objects decoded from YAML are keyed by any type
+			converted := make(map[string]interface{}, len(nested))
+			for key, value := range nested {
+				converted[fmt.Sprint(key)] = value
+			}
+
+			flatten(flattened, name, converted)
+			continue
+		}
+
+		flattened[name] = flatValue(value)
+	}
+}
+
+// flatName converts a key into a part of an env name by uppercasing it and
+// replacing anything that is not a letter or digit with an underscore
+func flatName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+
+		return '_'
+	}, name)
+}
+
+// flatValue formats the decoded JSON value as it would be written in an env.
+// Lists of values are joined with a comma, the default Delimiter.
+func flatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, elem := range v {
+			values[i] = flatValue(elem)
+		}
+
+		return strings.Join(values, ",")
+	}
+
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -0,0 +1,58 @@
+package envstruct
+
+// FetchEnvFrom will fetch the envs into the struct the same way as FetchEnv,
+// except that the envs are looked up within the values rather than the
+// environment of the process. Any source named SourceEnv is replaced by the
+// values wherever it is within the Sources (or by default), including the
+// sources within a Chain or MapNames, and any other sources are still used.
+// It is meant for tests, which can then run in parallel rather than racing
+// with each other through os.Setenv.
+func (e Envstruct) FetchEnvFrom(values map[string]string, object interface{}) error {
+	sources := e.sources()
+
+	e.Sources = make([]Source, len(sources))
+	for i, source := range sources {
+		e.Sources[i] = replaceEnvironment(source, valuesSource(values))
+	}
+
+	return e.FetchEnv(object)
+}
+
+// replaceEnvironment returns the source with the environment replaced by the
+// values. The environment is matched on its name, so that sources standing in
+// for it, such as a LookupFunc named SourceEnv, are replaced as well.
+func replaceEnvironment(source Source, values valuesSource) Source {
+	switch s := source.(type) {
+	case mappedSource:
+		s.source = replaceEnvironment(s.source, values)
+		return s
+
+	case chain:
+		sources := make([]Source, len(s.sources))
+		for i, source := range s.sources {
+			sources[i] = replaceEnvironment(source, values)
+		}
+
+		s.sources = sources
+		return s
+	}
+
+	if source.Name() == SourceEnv {
+		return values
+	}
+
+	return source
+}
+
+// valuesSource is a Source that stands in for the Environment, so it has the
+// same name and fields pinned to the environment are still fetched from it
+type valuesSource map[string]string
+
+func (v valuesSource) Name() string { return SourceEnv }
+
+func (v valuesSource) Lookup(envName string) (string, bool) {
+	value, found := v[envName]
+	return value, found
+}
+
+func (v valuesSource) Names() []string { return mapNames(v) }
@@ -0,0 +1,76 @@
+package envstruct_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestFetchEnvFrom() {
+	type Config struct {
+		Host  string   `tag:"host" source:"env"`
+		Port  int      `tag:"port"`
+		Peers []string `tag:"peers"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:        "app",
+		TagName:       "tag",
+		SourceTagName: "source",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("looks up the envs in the values instead of the environment", func() {
+		os.Setenv("APP_HOST", "from env")
+		os.Setenv("APP_PORT", "1234")
+
+		var config Config
+		err := env.FetchEnvFrom(map[string]string{
+			"APP_HOST":  "localhost",
+			"APP_PEERS": "a,b",
+		}, &config)
+		s.NoError(err)
+
+		s.Equal(Config{Host: "localhost", Peers: []string{"a", "b"}}, config)
+	})
+
+	s.Run("keeps the other sources", func() {
+		env := env
+		env.Sources = []envstruct.Source{
+			envstruct.Environment,
+			mapSource{"APP_PORT": "8080", "APP_HOST": "from map"},
+		}
+
+		var config Config
+		err := env.FetchEnvFrom(map[string]string{"APP_HOST": "localhost"}, &config)
+		s.NoError(err)
+
+		s.Equal(Config{Host: "localhost", Port: 8080}, config)
+	})
+
+	s.Run("replaces the environment within other sources and by its name", func() {
+		os.Setenv("APP_HOST", "from env")
+		os.Setenv("APP_PORT", "1234")
+		os.Setenv("PEERS", "from env")
+
+		env := env
+		env.Sources = []envstruct.Source{
+			envstruct.LookupFunc(envstruct.SourceEnv, os.LookupEnv),
+			envstruct.Chain("layered", envstruct.Environment, mapSource{"APP_PORT": "8080"}),
+			envstruct.MapNames(envstruct.Environment, func(envName string) string {
+				return strings.TrimPrefix(envName, "APP_")
+			}),
+		}
+
+		var config Config
+		err := env.FetchEnvFrom(map[string]string{"APP_HOST": "localhost", "PEERS": "a,b"}, &config)
+		s.NoError(err)
+
+		s.Equal(Config{Host: "localhost", Port: 8080, Peers: []string{"a", "b"}}, config)
+	})
+}
@@ -0,0 +1,126 @@
+// Package fswatch reloads an envstruct.Watcher whenever the files that its
+// sources are read from change, using fsnotify. This covers .env and config
+// files as well as secrets mounted into Kubernetes pods, which are rotated in
+// place.
+//
+// It is a separate module so that applications that do not watch files do not
+// pull in fsnotify.
+package fswatch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Debounce is how long to wait after a change before reloading, so that a
+// burst of changes, for ex. an editor writing a file or Kubernetes swapping a
+// mounted directory, only causes a single reload.
+const Debounce = 100 * time.Millisecond
+
+// FileWatcher watches files and reloads the envstruct.Watcher when they change
+type FileWatcher struct {
+	watcher  *fsnotify.Watcher
+	reloader *envstruct.Watcher
+	onReload func([]envstruct.Change, error)
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Watch starts watching the files of the sources of the Watcher, along with
+// any other paths, for ex. files that are read through tags. Whenever they
+// change, the Watcher is reloaded and onReload is called with the changed
+// fields or the error that the reload failed with. Reloads that do not change
+// any fields are not reported.
+//
+// The directory holding each file is watched rather than the file itself, as
+// files are often replaced rather than written to, which is how Kubernetes
+// rotates mounted secrets.
+func Watch(reloader *envstruct.Watcher, onReload func([]envstruct.Change, error), paths ...string) (*FileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	watched := map[string]bool{}
+	for _, path := range append(reloader.Files(), paths...) {
+		dir := path
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+
+		if watched[dir] {
+			continue
+		}
+
+		err = watcher.Add(dir)
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+
+		watched[dir] = true
+	}
+
+	w := &FileWatcher{
+		watcher:  watcher,
+		reloader: reloader,
+		onReload: onReload,
+		done:     make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w, nil
+}
+
+func (w *FileWatcher) run() {
+	defer w.wg.Done()
+
+	// The timer is only started once there is a change to debounce
+	timer := time.NewTimer(Debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			timer.Stop()
+			return
+
+		case _, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			timer.Reset(Debounce)
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			w.onReload(nil, err)
+
+		case <-timer.C:
+			changes, err := w.reloader.Reload()
+			if err != nil || len(changes) > 0 {
+				w.onReload(changes, err)
+			}
+		}
+	}
+}
+
+// Close stops watching the files
+func (w *FileWatcher) Close() error {
+	close(w.done)
+	err := w.watcher.Close()
+	w.wg.Wait()
+
+	return err
+}
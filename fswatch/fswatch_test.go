@@ -0,0 +1,77 @@
+package fswatch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"github.com/clarafu/envstruct/fswatch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	type Config struct {
+		Host  string `env:"host"`
+		Token string `env:"token,secret"`
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(path, []byte("HOST=example.com\n"), 0600))
+
+	secrets := filepath.Join(t.TempDir(), "secrets")
+	require.NoError(t, os.Mkdir(secrets, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(secrets, "token"), []byte("abc"), 0600))
+
+	file, err := envstruct.ReadDotenvFile(path)
+	require.NoError(t, err)
+
+	env := envstruct.Envstruct{
+		TagName: "env",
+		Sources: []envstruct.Source{file, envstruct.CredentialsDirectory{Dir: secrets}},
+
+		Parser: envstruct.Parser{Unmarshaler: envstruct.Unmarshal},
+	}
+
+	var config Config
+	watcher, err := envstruct.NewWatcher(env, &config)
+	require.NoError(t, err)
+
+	reloads := make(chan []envstruct.Change, 10)
+	fileWatcher, err := fswatch.Watch(watcher, func(changes []envstruct.Change, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+
+		reloads <- changes
+	})
+	require.NoError(t, err)
+	defer fileWatcher.Close()
+
+	// Replace the file rather than writing to it, the same as an editor would
+	replacement := filepath.Join(dir, ".env.tmp")
+	require.NoError(t, os.WriteFile(replacement, []byte("HOST=example.org\n"), 0600))
+	require.NoError(t, os.Rename(replacement, path))
+
+	select {
+	case changes := <-reloads:
+		require.Equal(t, []envstruct.Change{{Name: "HOST", Old: "example.com", New: "example.org"}}, changes)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the file change to be reloaded")
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(secrets, "token"), []byte("def"), 0600))
+
+	select {
+	case changes := <-reloads:
+		require.Equal(t, []envstruct.Change{{Name: "TOKEN", Old: "******", New: "******"}}, changes)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the secret change to be reloaded")
+	}
+
+	watcher.RLock()
+	require.Equal(t, Config{Host: "example.org", Token: "def"}, config)
+	watcher.RUnlock()
+}
@@ -0,0 +1,74 @@
+package envstruct_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func FuzzBuildName(f *testing.F) {
+	f.Add("prefix", "db", "host")
+	f.Add("", "|", " ")
+	f.Add("PREFIX", "addr-1", "ß")
+
+	f.Fuzz(func(t *testing.T, prefix, nested, name string) {
+		built := envstruct.BuildName([]string{prefix, nested, name})
+
+		if built != strings.ToUpper(built) {
+			t.Errorf("name %q is not uppercase", built)
+		}
+
+		if !strings.Contains(prefix+nested+name, "_") && strings.Count(built, "_") != 2 {
+			t.Errorf("name %q does not contain exactly two separators", built)
+		}
+	})
+}
+
+func FuzzParseValue(f *testing.F) {
+	f.Add("foo,bar")
+	f.Add("1,2,3")
+	f.Add("key:1,other:2")
+	f.Add("5m")
+	f.Add(",,:")
+	f.Add(`"a,b",c`)
+
+	types := []reflect.Type{
+		reflect.TypeOf(""),
+		reflect.TypeOf(0),
+		reflect.TypeOf(uint8(0)),
+		reflect.TypeOf(0.0),
+		reflect.TypeOf(false),
+		reflect.TypeOf(time.Duration(0)),
+		reflect.TypeOf(time.Time{}),
+		reflect.TypeOf(new(int)),
+		reflect.TypeOf([]string{}),
+		reflect.TypeOf([]int{}),
+		reflect.TypeOf(map[string]int{}),
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		for _, valueType := range types {
+			value, err := envstruct.ParseValue(valueType, raw)
+			if err != nil {
+				continue
+			}
+
+			if value.Type() != valueType {
+				t.Errorf("parsing %q as %s returned a %s", raw, valueType, value.Type())
+			}
+
+			// Quoted items can contain the delimiter, so the elements can only be
+			// counted from the delimiters when there are no quotes
+			if valueType.Kind() == reflect.Slice && !strings.Contains(raw, `"`) && value.Len() != strings.Count(raw, ",")+1 {
+				t.Errorf("parsing %q as %s returned %d elements", raw, valueType, value.Len())
+			}
+
+			if valueType.Kind() == reflect.String && value.String() != raw {
+				t.Errorf("parsing %q as a string returned %q", raw, value.String())
+			}
+		}
+	})
+}
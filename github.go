@@ -0,0 +1,54 @@
+package envstruct
+
+import (
+	"os"
+	"strings"
+)
+
+// SourceGitHubActions is the name of the GitHubActions Source
+const SourceGitHubActions = "github_actions"
+
+// GitHubActions is a Source for actions written in Go, which maps the inputs
+// of the action onto the fields of a struct. GitHub passes each input as an
+// env named INPUT_<NAME>, with the name of the input uppercased and spaces
+// replaced with underscores, while dashes are kept as is. The source looks up
+// the input without the INPUT_ prefix and treats spaces, dashes and
+// underscores as the same, so an input named "api-key" can be fetched with a
+// tag of "api-key" or "api_key". The values are trimmed of whitespace, the
+// same as the actions toolkit does.
+var GitHubActions Source = githubActions{}
+
+type githubActions struct{}
+
+func (githubActions) Name() string { return SourceGitHubActions }
+
+func (githubActions) Lookup(envName string) (string, bool) {
+	// Try the exact name first, which is the common case
+	if value, found := os.LookupEnv("INPUT_" + envName); found {
+		return strings.TrimSpace(value), true
+	}
+
+	want := githubInputName(envName)
+	for _, env := range os.Environ() {
+		keyVal := strings.SplitN(env, "=", 2)
+		if len(keyVal) != 2 || !strings.HasPrefix(keyVal[0], "INPUT_") {
+			continue
+		}
+
+		if githubInputName(strings.TrimPrefix(keyVal[0], "INPUT_")) == want {
+			return strings.TrimSpace(keyVal[1]), true
+		}
+	}
+
+	return "", false
+}
+
+// githubInputNamer replaces the characters that are treated as underscores
+// within the name of an input
+var githubInputNamer = strings.NewReplacer(" ", "_", "-", "_")
+
+// githubInputName will mangle the input name the same way that GitHub does,
+// but also replacing dashes so that they match underscores
+func githubInputName(name string) string {
+	return githubInputNamer.Replace(strings.ToUpper(name))
+}
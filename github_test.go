@@ -0,0 +1,46 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestGitHubActions() {
+	type Inputs struct {
+		Token   string   `tag:"token"`
+		APIKey  string   `tag:"api_key"`
+		Retries int      `tag:"max retries"`
+		Labels  []string `tag:"labels"`
+		Dryrun  bool     `tag:"dry-run"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		TagName: "tag",
+		Sources: []envstruct.Source{envstruct.GitHubActions},
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	os.Setenv("INPUT_TOKEN", " secret\n")
+	os.Setenv("INPUT_API-KEY", "key")
+	os.Setenv("INPUT_MAX_RETRIES", "3")
+	os.Setenv("INPUT_LABELS", "bug,help wanted")
+	os.Setenv("INPUT_DRY-RUN", "true")
+	os.Setenv("TOKEN", "not an input")
+
+	var inputs Inputs
+	err := env.FetchEnv(&inputs)
+	s.NoError(err)
+
+	s.Equal(Inputs{
+		Token:   "secret",
+		APIKey:  "key",
+		Retries: 3,
+		Labels:  []string{"bug", "help wanted"},
+		Dryrun:  true,
+	}, inputs)
+}
@@ -1,10 +1,14 @@
 module github.com/clarafu/envstruct
 
-go 1.14
+go 1.20
 
 require (
-	github.com/fatih/structs v1.1.0
-	github.com/mitchellh/mapstructure v1.3.3
 	github.com/stretchr/testify v1.6.1
 	gopkg.in/yaml.v2 v2.3.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
@@ -0,0 +1,241 @@
+package envstruct
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// defaultDescriptionTagName is the DescriptionTagName when it is not set
+const defaultDescriptionTagName = "desc"
+
+// Help renders a help section describing every env that the struct is
+// fetched from, meant to be embedded in the --help output of a tool. Each env
+// is listed with the type of its field, its description and the value already
+// set on the field as its default, for ex.
+//
+//	Environment variables:
+//	  APP_PORT  int     listen port (default 8080)
+//	  APP_HOST  string  listen address (required)
+//
+// The default of a secret is never shown.
+func (e Envstruct) Help(object interface{}) (string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return "", errors.New("failed to render help for object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return "", err
+	}
+
+	var table bytes.Buffer
+
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	for _, f := range fields {
+		if !f.tagged || len(f.envNames) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", strings.Join(f.envNames, ", "), typeName(f.description.Type), e.usage(f))
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return "", err
+	}
+
+	// Remove the padding that is left behind on envs without a description
+	help := "Environment variables:\n"
+	for _, line := range strings.SplitAfter(table.String(), "\n") {
+		if line != "" {
+			help += strings.TrimRight(line, " \n") + "\n"
+		}
+	}
+
+	return help, nil
+}
+
+// Usage writes a table describing every env that the struct is fetched from
+// to the writer, with the name, type, default, whether it is required and the
+// description of each env, for ex.
+//
+//	KEY       TYPE    DEFAULT  REQUIRED  DESCRIPTION
+//	APP_PORT  int     8080               listen port
+//	APP_HOST  string           true      listen address
+//
+// The value already set on a field is used as its default, unless it has one
+// within the DefaultTagName. The default of a secret is redacted.
+func (e Envstruct) Usage(w io.Writer, object interface{}) error {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return errors.New("failed to render usage for object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return err
+	}
+
+	var table bytes.Buffer
+
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+	for _, f := range fields {
+		if !f.tagged || len(f.envNames) == 0 {
+			continue
+		}
+
+		spec := e.fieldSpec(f)
+
+		var required string
+		if spec.Required {
+			required = "true"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", strings.Join(spec.EnvNames, ", "), typeName(f.description.Type), spec.Default, required, spec.Description)
+	}
+
+	err = tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	// Remove the padding that is left behind on envs without a description
+	for _, line := range strings.SplitAfter(table.String(), "\n") {
+		if line == "" {
+			continue
+		}
+
+		_, err := io.WriteString(w, strings.TrimRight(line, " \n")+"\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// description returns the description of the field from the
+// DescriptionTagName
+func (e Envstruct) description(f *field) string {
+	tagName := e.DescriptionTagName
+	if tagName == "" {
+		tagName = defaultDescriptionTagName
+	}
+
+	return strings.TrimSpace(f.description.Tag.Get(tagName))
+}
+
+// usage returns the description of the field along with whether it is
+// required or its default value, with the default from the DefaultTagName
+// taking precedence over the value already set on the field
+func (e Envstruct) usage(f *field) string {
+	defaultValue, hasDefault := e.defaultValue(f)
+
+	var notes []string
+	switch {
+	case hasDefault && f.options.secret:
+		notes = append(notes, "default set")
+	case hasDefault:
+		notes = append(notes, "default "+defaultValue)
+	case e.required(f):
+		notes = append(notes, "required")
+	case f.value.IsZero():
+	case f.options.secret:
+		notes = append(notes, "default set")
+	default:
+		notes = append(notes, fmt.Sprintf("default %v", formatValue(f.value, e.fieldParser(f.options))))
+	}
+
+	usage := e.description(f)
+	if len(notes) > 0 {
+		usage = strings.TrimSpace(fmt.Sprintf("%s (%s)", usage, strings.Join(notes, ", ")))
+	}
+
+	return usage
+}
+
+// formatValue formats the value the same way that it would be written in the
+// env, with the elements of slices and maps separated by the delimiter of the
+// parser, and the keys and values of maps by its separator. The elements of
+// nested slices and maps are separated by the outer delimiter instead, with
+// each of them formatted as a slice or map of its own. Elements that contain
+// the delimiter are quoted.
+func formatValue(v reflect.Value, parser Parser) string {
+	value := dumpValue(v, false)
+
+	rv := reflect.ValueOf(value)
+
+	delimiter := parser.delimiter()
+	nested := rv.IsValid() && isNestedCollection(rv.Type())
+	if nested {
+		delimiter = parser.outerDelimiter()
+	}
+
+	formatElem := func(elem reflect.Value) string {
+		if nested {
+			return formatValue(elem, parser)
+		}
+
+		return fmt.Sprint(elem.Interface())
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		elems := make([]string, rv.Len())
+		for i := range elems {
+			elems[i] = quoteItem(formatElem(rv.Index(i)), delimiter)
+		}
+
+		return strings.Join(elems, delimiter)
+
+	case reflect.Map:
+		var entries []string
+		iter := rv.MapRange()
+		for iter.Next() {
+			entry := fmt.Sprintf("%v%s%s", iter.Key().Interface(), parser.separator(), formatElem(iter.Value()))
+			entries = append(entries, quoteItem(entry, delimiter))
+		}
+
+		sort.Strings(entries)
+		return strings.Join(entries, delimiter)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// typeName returns a short name for the type of the field, in the same style
+// as the flag package
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Optional values are described by the type of their value
+	if isOptional(t) {
+		return typeName(t.Field(0).Type)
+	}
+
+	switch t {
+	case durationType:
+		return "duration"
+	case timeType:
+		return "time"
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		return "list of " + typeName(t.Elem())
+	case reflect.Map:
+		return "map of " + typeName(t.Key()) + " to " + typeName(t.Elem())
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	}
+
+	return t.Kind().String()
+}
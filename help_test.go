@@ -0,0 +1,118 @@
+package envstruct_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestHelp() {
+	type Config struct {
+		Port     int           `tag:"port" desc:"listen port"`
+		Host     string        `tag:"host,optional" desc:"listen address"`
+		Timeout  time.Duration `tag:"timeout" desc:"request timeout"`
+		Peers    []string      `tag:"peers" desc:"addresses of the other nodes"`
+		Password string        `tag:"password,secret" desc:"admin password"`
+		Address  string        `tag:"addr|address"`
+		Internal string
+	}
+
+	config := Config{
+		Port:     8080,
+		Timeout:  30 * time.Second,
+		Peers:    []string{"a", "b"},
+		Password: "hunter2",
+	}
+
+	s.Run("describes each env with its type and default", func() {
+		env := envstruct.Envstruct{
+			Prefix:  "app",
+			TagName: "tag",
+		}
+
+		help, err := env.Help(&config)
+		s.NoError(err)
+
+		s.Equal(`Environment variables:
+  APP_PORT               int             listen port (default 8080)
+  APP_HOST               string          listen address
+  APP_TIMEOUT            duration        request timeout (default 30s)
+  APP_PEERS              list of string  addresses of the other nodes (default a,b)
+  APP_PASSWORD           string          admin password (default set)
+  APP_ADDR, APP_ADDRESS  string
+`, help)
+	})
+
+	s.Run("marks required envs", func() {
+		env := envstruct.Envstruct{
+			Prefix:             "app",
+			TagName:            "tag",
+			RequireAll:         true,
+			DescriptionTagName: "help",
+		}
+
+		type Config struct {
+			Port int    `tag:"port" help:"listen port"`
+			Host string `tag:"host,optional" help:"listen address"`
+		}
+
+		help, err := env.Help(&Config{})
+		s.NoError(err)
+
+		s.Equal(`Environment variables:
+  APP_PORT  int     listen port (required)
+  APP_HOST  string  listen address
+`, help)
+	})
+	s.Run("shows the defaults from the DefaultTagName", func() {
+		env := envstruct.Envstruct{
+			Prefix:         "app",
+			TagName:        "tag",
+			RequireAll:     true,
+			DefaultTagName: "default",
+		}
+
+		type Config struct {
+			Port     int    `tag:"port" default:"8080" desc:"listen port"`
+			Password string `tag:"password,secret" default:"hunter2"`
+		}
+
+		help, err := env.Help(&Config{})
+		s.NoError(err)
+
+		s.Equal(`Environment variables:
+  APP_PORT      int     listen port (default 8080)
+  APP_PASSWORD  string  (default set)
+`, help)
+	})
+}
+
+func (s *EnvstructSuite) TestUsage() {
+	type Config struct {
+		Port     int           `tag:"port" desc:"listen port"`
+		Host     string        `tag:"host,required" desc:"listen address"`
+		Timeout  time.Duration `tag:"timeout" default:"30s"`
+		Password string        `tag:"password,secret" desc:"admin password"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:         "app",
+		TagName:        "tag",
+		DefaultTagName: "default",
+	}
+
+	s.Run("writes a table of each env", func() {
+		var usage strings.Builder
+		err := env.Usage(&usage, &Config{Port: 8080, Password: "hunter2"})
+		s.NoError(err)
+
+		s.Equal(`KEY           TYPE      DEFAULT  REQUIRED  DESCRIPTION
+APP_PORT      int       8080               listen port
+APP_HOST      string             true      listen address
+APP_TIMEOUT   duration  30s
+APP_PASSWORD  string    ******             admin password
+`, usage.String())
+	})
+}
@@ -0,0 +1,59 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestHexOption() {
+	type Config struct {
+		HMACKey [4]byte `tag:"hmac_key,hex,secret"`
+		Salt    []byte  `tag:"salt,hex"`
+		Port    int     `tag:"port,hex"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("decodes the value into byte slices and arrays", func() {
+		os.Setenv("APP_HMAC_KEY", "deadBEEF")
+		os.Setenv("APP_SALT", "0x00ff10")
+		os.Setenv("APP_PORT", "38303830")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			HMACKey: [4]byte{0xde, 0xad, 0xbe, 0xef},
+			Salt:    []byte{0x00, 0xff, 0x10},
+			Port:    8080,
+		}, config)
+	})
+
+	s.Run("fails when the value does not fit the array", func() {
+		os.Clearenv()
+		os.Setenv("APP_HMAC_KEY", "deadbeef00")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field HMACKey (env APP_HMAC_KEY): decoded value is 5 bytes, needs to be 4 bytes")
+	})
+
+	s.Run("fails on values that are not hex", func() {
+		os.Clearenv()
+		os.Setenv("APP_SALT", "xyz")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field Salt (env APP_SALT): invalid hex: encoding/hex: invalid byte: U+0078 'x'")
+	})
+}
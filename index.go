@@ -0,0 +1,105 @@
+package envstruct
+
+import (
+	"os"
+	"strings"
+)
+
+// IndexedSource is implemented by sources that can list every value that they
+// hold at once, for ex. with a single request to a remote service. The index
+// is built once at the start of each fetch and every candidate name of every
+// field is looked up within it, rather than calling Lookup for each of them,
+// which adds up for very large structs.
+type IndexedSource interface {
+	Source
+
+	// Index returns every value of the source keyed by the name of its env, or
+	// nil if the source can not be indexed, in which case Lookup is used. The
+	// map is not modified by the caller.
+	Index() (map[string]string, error)
+}
+
+// indexedSource is a Source that looks up envs within the index of an
+// IndexedSource
+type indexedSource struct {
+	name   string
+	values map[string]string
+
+	// foldCase is true if the names within the index are uppercased, so that
+	// they are looked up case insensitively
+	foldCase bool
+}
+
+// caseFolder is implemented by an IndexedSource whose envs are case
+// insensitive, in which case its Index is keyed by the uppercased names
+type caseFolder interface {
+	foldsCase() bool
+}
+
+func (s indexedSource) Name() string { return s.name }
+
+func (s indexedSource) Lookup(envName string) (string, bool) {
+	if s.foldCase {
+		envName = strings.ToUpper(envName)
+	}
+
+	value, found := s.values[envName]
+	return value, found
+}
+
+func (s indexedSource) Names() []string { return mapNames(s.values) }
+
+// indexSources returns the sources with every IndexedSource replaced by its
+// index
+func (e Envstruct) indexSources() ([]Source, error) {
+	sources := e.sources()
+
+	indexed := make([]Source, len(sources))
+	for i, source := range sources {
+		indexed[i] = source
+
+		indexable, ok := source.(IndexedSource)
+		if !ok {
+			continue
+		}
+
+		values, err := indexable.Index()
+		if err != nil {
+			return nil, &SourceError{Source: source.Name(), Err: err}
+		}
+
+		if values != nil {
+			folder, ok := source.(caseFolder)
+			indexed[i] = indexedSource{name: source.Name(), values: values, foldCase: ok && folder.foldsCase()}
+		}
+	}
+
+	return indexed, nil
+}
+
+// Index returns every env of the process, so that the environment is read once
+// per fetch rather than once for every candidate name, which is a system call
+// per name on Windows. The names of envs are case insensitive on Windows, in
+// which case the index is keyed by the uppercased names, see foldsCase.
+func (environment) Index() (map[string]string, error) {
+	environ := os.Environ()
+	fold := environment{}.foldsCase()
+
+	values := make(map[string]string, len(environ))
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+
+		// Special entries on Windows such as "=C:=C:\" are not envs
+		if name == "" {
+			continue
+		}
+
+		if fold {
+			name = strings.ToUpper(name)
+		}
+
+		values[name] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package envstruct
+
+// foldsCase returns false, as the names of envs are case sensitive outside of
+// Windows
+func (environment) foldsCase() bool { return false }
@@ -0,0 +1,112 @@
+package envstruct_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+// countingSource is an IndexedSource that counts how it is read
+type countingSource struct {
+	values  map[string]string
+	lookups int
+	indexes int
+}
+
+func (c *countingSource) Name() string { return "counting" }
+
+func (c *countingSource) Lookup(envName string) (string, bool) {
+	c.lookups++
+	value, found := c.values[envName]
+	return value, found
+}
+
+func (c *countingSource) Index() (map[string]string, error) {
+	c.indexes++
+	return c.values, nil
+}
+
+func (s *EnvstructSuite) TestIndexedSource() {
+	type Config struct {
+		Host string `tag:"host"`
+		Port int    `tag:"port"`
+		User string `tag:"user"`
+	}
+
+	defer os.Clearenv()
+
+	source := &countingSource{values: map[string]string{"HOST": "localhost", "PORT": "5432"}}
+	env := envstruct.Envstruct{
+		TagName: "tag",
+		Sources: []envstruct.Source{source, envstruct.Environment},
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	os.Setenv("USER", "admin")
+
+	var config Config
+	err := env.FetchEnv(&config)
+	s.NoError(err)
+	s.Equal(Config{Host: "localhost", Port: 5432, User: "admin"}, config)
+
+	s.Equal(0, source.lookups)
+	s.Equal(1, source.indexes)
+
+	// The environment is indexed as well, on every platform
+	indexable, ok := envstruct.Environment.(envstruct.IndexedSource)
+	s.True(ok)
+
+	values, err := indexable.Index()
+	s.NoError(err)
+	s.Equal("admin", values["USER"])
+}
+
+func BenchmarkFetchEnv(b *testing.B) {
+	type Nested struct {
+		A string `env:"a"`
+		B int    `env:"b"`
+		C bool   `env:"c"`
+		D string `env:"d"`
+	}
+
+	type Config struct {
+		N0 Nested `env:"n0"`
+		N1 Nested `env:"n1"`
+		N2 Nested `env:"n2"`
+		N3 Nested `env:"n3"`
+		N4 Nested `env:"n4"`
+		N5 Nested `env:"n5"`
+		N6 Nested `env:"n6"`
+		N7 Nested `env:"n7"`
+		N8 Nested `env:"n8"`
+		N9 Nested `env:"n9"`
+	}
+
+	os.Clearenv()
+	defer os.Clearenv()
+
+	for i := 0; i < 30; i++ {
+		os.Setenv(fmt.Sprintf("UNRELATED_%d", i), "value")
+	}
+	os.Setenv("APP_N0_B", "1")
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "env",
+
+		Parser: envstruct.Parser{Unmarshaler: envstruct.Unmarshal},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var config Config
+		err := env.FetchEnv(&config)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
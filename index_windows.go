@@ -0,0 +1,6 @@
+//go:build windows
+
+package envstruct
+
+// foldsCase returns true, as the names of envs are case insensitive on Windows
+func (environment) foldsCase() bool { return true }
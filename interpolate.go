@@ -0,0 +1,141 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// interpolationPattern matches a reference to another field within a value,
+// for ex. "{{.Server.Host}}".
+var interpolationPattern = regexp.MustCompile(`{{\s*\.([A-Za-z_][A-Za-z0-9_.]*)\s*}}`)
+
+// interpolate will replace any references to other fields within the raw
+// values of the plan with the values of those fields. If the referenced field
+// was also fetched, its (interpolated) raw value is used, otherwise the value
+// that is already set on the struct is used. References are resolved in
+// dependency order and cycles are reported as errors. In best effort mode, any
+// assignment that fails to be interpolated is dropped from the plan.
+func (p *plan) interpolate(root reflect.Value, bestEffort bool) error {
+	staged := map[string]*assignment{}
+	for _, a := range p.assignments {
+		staged[a.path] = a
+	}
+
+	resolved := map[string]bool{}
+	visiting := map[string]bool{}
+
+	var resolve func(a *assignment) error
+	resolve = func(a *assignment) error {
+		if resolved[a.path] {
+			return nil
+		}
+
+		// If we come back to a field that is still being resolved then the fields
+		// reference each other
+		if visiting[a.path] {
+			return fmt.Errorf("interpolation cycle detected at field %s", a.path)
+		}
+
+		visiting[a.path] = true
+		defer delete(visiting, a.path)
+
+		var err error
+		a.raw = interpolationPattern.ReplaceAllStringFunc(a.raw, func(match string) string {
+			if err != nil {
+				return match
+			}
+
+			reference := interpolationPattern.FindStringSubmatch(match)[1]
+
+			// Resolve the referenced field first if it was also fetched, so that its
+			// value is fully interpolated before it is used
+			if dependency, ok := staged[reference]; ok {
+				err = resolve(dependency)
+				return dependency.raw
+			}
+
+			field, ok := fieldByPath(root, reference)
+			if !ok {
+				err = fmt.Errorf("%s references unknown field %s", a.name, reference)
+				return match
+			}
+
+			return formatField(field)
+		})
+		if err != nil {
+			return err
+		}
+
+		resolved[a.path] = true
+
+		return nil
+	}
+
+	var errs Errors
+
+	interpolated := make([]*assignment, 0, len(p.assignments))
+	for _, a := range p.assignments {
+		err := resolve(a)
+		if err != nil {
+			if !bestEffort {
+				return err
+			}
+
+			errs = errs.collect(err)
+			continue
+		}
+
+		interpolated = append(interpolated, a)
+	}
+
+	p.assignments = interpolated
+
+	return errs.errorOrNil()
+}
+
+// fieldByPath will find the field within the struct using the path of field
+// names separated by dots. A nil pointer along the way results in an invalid
+// value, which is treated as empty.
+func fieldByPath(root reflect.Value, path string) (reflect.Value, bool) {
+	v := root
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, true
+			}
+
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		v = v.FieldByName(name)
+		if !v.IsValid() || !v.CanInterface() {
+			return reflect.Value{}, false
+		}
+	}
+
+	return v, true
+}
+
+// formatField formats the value of a field so that it can be interpolated into
+// another value.
+func formatField(v reflect.Value) string {
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprint(v.Interface())
+}
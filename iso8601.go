@@ -0,0 +1,182 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatISO8601 is the value of the "format" tag option that parses
+// time.Duration values as ISO 8601 durations, for ex. "PT1H30M", and
+// time.Time values as ISO 8601 dates, including ordinal dates such as
+// "2024-153" and week dates such as "2024-W23-2".
+const FormatISO8601 = "iso8601"
+
+// isoDuration matches an ISO 8601 duration. Years and months are matched so
+// that a clear error can be given, as their length varies.
+var isoDuration = regexp.MustCompile(`^([-+])?P(?:(\d+(?:[.,]\d+)?)Y)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// isoDurationUnits are the units of each of the groups of isoDuration after
+// the sign, zero for years and months
+var isoDurationUnits = []time.Duration{0, 0, 7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+
+// parseISODuration parses an ISO 8601 duration. Days are taken to be 24
+// hours long.
+func parseISODuration(value string) (time.Duration, error) {
+	match := isoDuration.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("failed to parse %q as an ISO 8601 duration", value)
+	}
+
+	var total float64
+	for i, component := range match[2:] {
+		if component == "" {
+			continue
+		}
+
+		amount, err := strconv.ParseFloat(strings.Replace(component, ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %q as an ISO 8601 duration: %w", value, err)
+		}
+
+		if isoDurationUnits[i] == 0 {
+			if amount != 0 {
+				return 0, fmt.Errorf("failed to parse %q as an ISO 8601 duration: years and months are not supported as their length varies", value)
+			}
+
+			continue
+		}
+
+		total += amount * float64(isoDurationUnits[i])
+	}
+
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("failed to parse %q as an ISO 8601 duration: out of range", value)
+	}
+
+	duration := time.Duration(math.Round(total))
+	if match[1] == "-" {
+		duration = -duration
+	}
+
+	return duration, nil
+}
+
+// isoDate matches the extended and basic forms of ISO 8601 calendar, ordinal
+// and week dates
+var isoDate = regexp.MustCompile(`^(\d{4})(?:-?(\d{2})-?(\d{2})|-?(\d{3})|-?W(\d{2})(?:-?([1-7]))?)$`)
+
+// isoTimeLayouts are the layouts tried in order for the time of an ISO 8601
+// timestamp, after the "T"
+var isoTimeLayouts = []string{
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999",
+	"15:04Z07:00",
+	"15:04",
+	"150405.999999999Z0700",
+	"150405.999999999",
+	"1504Z0700",
+	"1504",
+}
+
+// parseISOTime parses an ISO 8601 date with an optional time. Values without
+// an offset are interpreted in the location.
+func parseISOTime(value string, location *time.Location) (time.Time, error) {
+	datePart, timePart, hasTime := strings.Cut(value, "T")
+
+	match := isoDate.FindStringSubmatch(datePart)
+	if match == nil {
+		return time.Time{}, fmt.Errorf("failed to parse %q as an ISO 8601 date", value)
+	}
+
+	year, _ := strconv.Atoi(match[1])
+
+	var date time.Time
+	switch {
+	case match[2] != "":
+		month, _ := strconv.Atoi(match[2])
+		day, _ := strconv.Atoi(match[3])
+		date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if date.Month() != time.Month(month) || date.Day() != day {
+			return time.Time{}, fmt.Errorf("failed to parse %q as an ISO 8601 date: day out of range", value)
+		}
+
+	case match[4] != "":
+		day, _ := strconv.Atoi(match[4])
+		date = time.Date(year, time.January, day, 0, 0, 0, 0, time.UTC)
+		if day < 1 || date.Year() != year {
+			return time.Time{}, fmt.Errorf("failed to parse %q as an ISO 8601 date: day out of range", value)
+		}
+
+	default:
+		week, _ := strconv.Atoi(match[5])
+		weekday := 1
+		if match[6] != "" {
+			weekday, _ = strconv.Atoi(match[6])
+		}
+
+		// The first week of the year is the week holding the 4th of January,
+		// and weeks start on a Monday
+		january4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+		firstMonday := january4.AddDate(0, 0, -((int(january4.Weekday()) + 6) % 7))
+		date = firstMonday.AddDate(0, 0, (week-1)*7+weekday-1)
+
+		if _, dateWeek := date.ISOWeek(); week < 1 || dateWeek != week {
+			return time.Time{}, fmt.Errorf("failed to parse %q as an ISO 8601 date: week out of range", value)
+		}
+	}
+
+	if !hasTime {
+		return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location), nil
+	}
+
+	for _, layout := range isoTimeLayouts {
+		t, err := time.ParseInLocation(layout, timePart, location)
+		if err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("failed to parse %q as an ISO 8601 time", value)
+}
+
+// unmarshalFormat will unmarshal the data into the value if the format of
+// the Parser applies to it, returning false if it does not
+func (p Parser) unmarshalFormat(data []byte, v interface{}) (bool, error) {
+	if p.format != FormatISO8601 {
+		return false, errors.New("unknown format " + strconv.Quote(p.format))
+	}
+
+	location := p.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	switch target := v.(type) {
+	case *time.Duration:
+		duration, err := parseISODuration(string(data))
+		*target = duration
+		return true, err
+
+	case **time.Duration:
+		duration, err := parseISODuration(string(data))
+		*target = &duration
+		return true, err
+
+	case *time.Time:
+		t, err := parseISOTime(string(data), location)
+		*target = t
+		return true, err
+
+	case **time.Time:
+		t, err := parseISOTime(string(data), location)
+		*target = &t
+		return true, err
+	}
+
+	return false, nil
+}
@@ -0,0 +1,117 @@
+package envstruct_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestISO8601() {
+	type Config struct {
+		Timeout   time.Duration   `tag:"timeout,format=iso8601"`
+		Intervals []time.Duration `tag:"intervals,format=iso8601"`
+		Start     time.Time       `tag:"start,format=iso8601"`
+		Deadline  *time.Time      `tag:"deadline,format=iso8601"`
+		Name      string          `tag:"name,format=iso8601"`
+		Other     time.Duration   `tag:"other"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	fetch := func(envs map[string]string) (Config, error) {
+		os.Clearenv()
+		for name, value := range envs {
+			os.Setenv(name, value)
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		return config, err
+	}
+
+	s.Run("parses ISO 8601 durations", func() {
+		for value, expected := range map[string]time.Duration{
+			"PT1H30M":    90 * time.Minute,
+			"PT0.5S":     500 * time.Millisecond,
+			"PT1,5M":     90 * time.Second,
+			"P1DT2H":     26 * time.Hour,
+			"P2W":        14 * 24 * time.Hour,
+			"-PT10S":     -10 * time.Second,
+			"P0Y0M1D":    24 * time.Hour,
+			"PT36H":      36 * time.Hour,
+			"P0DT0H0M1S": time.Second,
+		} {
+			config, err := fetch(map[string]string{"APP_TIMEOUT": value})
+			s.NoError(err, value)
+			s.Equal(expected, config.Timeout, value)
+		}
+
+		config, err := fetch(map[string]string{"APP_INTERVALS": "PT1S,PT1M", "APP_OTHER": "5s", "APP_NAME": "PT1S"})
+		s.NoError(err)
+		s.Equal([]time.Duration{time.Second, time.Minute}, config.Intervals)
+		s.Equal(5*time.Second, config.Other)
+		s.Equal("PT1S", config.Name)
+	})
+
+	s.Run("fails on invalid ISO 8601 durations", func() {
+		for _, value := range []string{"1h", "P", "PT", "P1M", "P1Y", "PT1H1D"} {
+			_, err := fetch(map[string]string{"APP_TIMEOUT": value})
+			s.Error(err, value)
+		}
+
+		_, err := fetch(map[string]string{"APP_TIMEOUT": "P1M"})
+		s.Contains(err.Error(), "years and months are not supported")
+	})
+
+	s.Run("parses ISO 8601 dates", func() {
+		june := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+		for value, expected := range map[string]time.Time{
+			"2024-06-01":                june,
+			"20240601":                  june,
+			"2024-153":                  june,
+			"2024153":                   june,
+			"2024-W22-6":                june,
+			"2024W226":                  june,
+			"2024-W01":                  time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			"2021-W01-1":                time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC),
+			"2020-W53-5":                time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+			"2024-153T03:04:05":         june.Add(3*time.Hour + 4*time.Minute + 5*time.Second),
+			"2024-W22-6T0304Z":          june.Add(3*time.Hour + 4*time.Minute),
+			"2024-06-01T03:04:05+02:00": time.Date(2024, time.June, 1, 3, 4, 5, 0, time.FixedZone("", 2*60*60)),
+		} {
+			config, err := fetch(map[string]string{"APP_START": value})
+			s.NoError(err, value)
+			s.True(expected.Equal(config.Start), "%s: %s", value, config.Start)
+		}
+
+		config, err := fetch(map[string]string{"APP_DEADLINE": "2024-153"})
+		s.NoError(err)
+		s.True(june.Equal(*config.Deadline))
+	})
+
+	s.Run("fails on invalid ISO 8601 dates", func() {
+		for _, value := range []string{"2024-02-30", "2023-366", "2021-W53-1", "2024-W00", "2024-153T25:00", "June 1"} {
+			_, err := fetch(map[string]string{"APP_START": value})
+			s.Error(err, value)
+		}
+	})
+
+	s.Run("fails on unknown formats", func() {
+		var config struct {
+			Timeout time.Duration `tag:"timeout,format=rfc"`
+		}
+
+		os.Setenv("APP_TIMEOUT", "1s")
+		err := env.FetchEnv(&config)
+		s.EqualError(err, `field Timeout (env APP_TIMEOUT): unknown format "rfc"`)
+	})
+}
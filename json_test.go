@@ -0,0 +1,65 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestJSONOption() {
+	type Server struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+
+	type Endpoint struct {
+		URL     string   `json:"url"`
+		Methods []string `json:"methods"`
+	}
+
+	type Config struct {
+		Servers   []Server            `tag:"servers,json"`
+		Endpoints map[string]Endpoint `tag:"endpoints,json"`
+		Primary   *Server             `tag:"primary,json"`
+		Matrix    [][]int             `tag:"matrix,json,base64"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("unmarshals the whole value as JSON", func() {
+		os.Setenv("APP_SERVERS", `[{"host": "a", "port": 1}, {"host": "b", "port": 2}]`)
+		os.Setenv("APP_ENDPOINTS", `{"users": {"url": "http://users", "methods": ["GET", "POST"]}}`)
+		os.Setenv("APP_PRIMARY", `{"host": "a", "port": 1}`)
+		os.Setenv("APP_MATRIX", "W1sxLDJdLFszLDRdXQ==")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Servers: []Server{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+			Endpoints: map[string]Endpoint{
+				"users": {URL: "http://users", Methods: []string{"GET", "POST"}},
+			},
+			Primary: &Server{Host: "a", Port: 1},
+			Matrix:  [][]int{{1, 2}, {3, 4}},
+		}, config)
+	})
+
+	s.Run("fails on invalid JSON", func() {
+		os.Clearenv()
+		os.Setenv("APP_SERVERS", `[{"host": "a"`)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field Servers (env APP_SERVERS): unexpected end of JSON input")
+	})
+}
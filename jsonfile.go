@@ -0,0 +1,72 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SourceJSONFile is the name of the JSONFile Source
+const SourceJSONFile = "json_file"
+
+// JSONFile is a Source for a JSON config file. The file is flattened the same
+// way as the credentials of the VCAP Source, the keys of nested objects are
+// joined with an underscore and uppercased, and lists of values are joined
+// with a comma. For ex.
+//
+//	{"db": {"host": "localhost", "replicas": ["a", "b"]}}
+//
+// is exposed as DB_HOST=localhost and DB_REPLICAS=a,b.
+type JSONFile struct {
+	// Path is the path of the file that was read, empty if no file was read
+	Path string
+
+	*fileValues
+}
+
+// ReadJSONFile reads and flattens the JSON file at the path. The file needs to
+// contain a JSON object.
+func ReadJSONFile(path string) (*JSONFile, error) {
+	file := &JSONFile{Path: path, fileValues: newFileValues(path, readJSONFile)}
+
+	err := file.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func readJSONFile(path string) (map[string]string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var parsed map[string]interface{}
+	err = json.Unmarshal(contents, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flatten(values, "", parsed)
+
+	return values, nil
+}
+
+// ReadJSONFileFromEnv reads the JSON file at the path set in the env, for ex.
+// APP_CONFIG_FILE, so that the location of the file can be configured the same
+// way as everything else. If the env is not set, the returned source is empty
+// so that the file is optional.
+func ReadJSONFileFromEnv(envName string) (*JSONFile, error) {
+	path := os.Getenv(envName)
+	if path == "" {
+		return &JSONFile{fileValues: newFileValues("", readJSONFile)}, nil
+	}
+
+	return ReadJSONFile(path)
+}
+
+// Name returns SourceJSONFile
+func (f *JSONFile) Name() string { return SourceJSONFile }
@@ -0,0 +1,71 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestJSONFile() {
+	type Config struct {
+		Database struct {
+			Host     string   `tag:"host"`
+			Port     int      `tag:"port"`
+			Replicas []string `tag:"replicas"`
+		} `tag:"db"`
+		Debug bool `tag:"debug"`
+	}
+
+	defer os.Clearenv()
+
+	path := filepath.Join(s.T().TempDir(), "config.json")
+	s.NoError(os.WriteFile(path, []byte(`{
+  "db": {"host": "localhost", "port": 5432, "replicas": ["a", "b"]},
+  "debug": true
+}`), 0600))
+
+	s.Run("reads the path of the file from the env", func() {
+		os.Setenv("APP_CONFIG_FILE", path)
+		os.Setenv("DB_PORT", "6543")
+
+		file, err := envstruct.ReadJSONFileFromEnv("APP_CONFIG_FILE")
+		s.NoError(err)
+		s.Equal(path, file.Path)
+
+		env := envstruct.Envstruct{
+			TagName: "tag",
+			Sources: []envstruct.Source{envstruct.Environment, file},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("localhost", config.Database.Host)
+		s.Equal(6543, config.Database.Port)
+		s.Equal([]string{"a", "b"}, config.Database.Replicas)
+		s.True(config.Debug)
+	})
+
+	s.Run("is empty when the env is not set", func() {
+		os.Clearenv()
+
+		file, err := envstruct.ReadJSONFileFromEnv("APP_CONFIG_FILE")
+		s.NoError(err)
+
+		_, found := file.Lookup("DB_HOST")
+		s.False(found)
+	})
+
+	s.Run("fails on a file that is not a JSON object", func() {
+		invalid := filepath.Join(s.T().TempDir(), "invalid.json")
+		s.NoError(os.WriteFile(invalid, []byte(`["a"]`), 0600))
+
+		_, err := envstruct.ReadJSONFile(invalid)
+		s.EqualError(err, "failed to parse config file "+invalid+": json: cannot unmarshal array into Go value of type map[string]interface {}")
+	})
+}
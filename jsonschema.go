@@ -0,0 +1,149 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// jsonSchemaDraft is the version of JSON Schema that JSONSchema is written in
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is the subset of a JSON Schema that is needed to describe the
+// envs of a struct
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Default     interface{}            `json:"default,omitempty"`
+	Enum        []interface{}          `json:"enum,omitempty"`
+	WriteOnly   bool                   `json:"writeOnly,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	AnyOf       []*jsonSchema          `json:"anyOf,omitempty"`
+	AllOf       []*jsonSchema          `json:"allOf,omitempty"`
+}
+
+// JSONSchema returns a JSON Schema of an object holding every env that the
+// struct is fetched from, so that for ex. Helm values or Terraform variables
+// can be validated against the configuration of an application. Each env is
+// described by the type of its field, its default, the values listed within
+// the EnumTagName and its description. Slices, maps, durations and any other
+// values without a JSON type of their own are described as strings, the same
+// way as they are written in the env. A required field with aliases only
+// needs one of its envs to be set. Secrets are marked as writeOnly, and their
+// defaults are left out.
+func (e Envstruct) JSONSchema(object interface{}) ([]byte, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to build json schema for object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &jsonSchema{
+		Schema:     jsonSchemaDraft,
+		Type:       "object",
+		Properties: map[string]*jsonSchema{},
+	}
+
+	for _, f := range fields {
+		if !f.tagged || len(f.envNames) == 0 {
+			continue
+		}
+
+		spec := e.fieldSpec(f)
+		jsonType := jsonSchemaType(f.description.Type)
+
+		property := &jsonSchema{
+			Type:        jsonType,
+			Description: spec.Description,
+			WriteOnly:   spec.Secret,
+		}
+
+		if spec.Default != "" && !spec.Secret {
+			property.Default = jsonSchemaValue(jsonType, spec.Default)
+		}
+
+		for _, value := range f.enum {
+			property.Enum = append(property.Enum, jsonSchemaValue(jsonType, value))
+		}
+
+		for _, envName := range f.envNames {
+			schema.Properties[envName] = property
+		}
+
+		if !spec.Required {
+			continue
+		}
+
+		if len(f.envNames) == 1 {
+			schema.Required = append(schema.Required, f.envNames[0])
+			continue
+		}
+
+		// Only one of the envs of a field with aliases needs to be set
+		var alternatives []*jsonSchema
+		for _, envName := range f.envNames {
+			alternatives = append(alternatives, &jsonSchema{Required: []string{envName}})
+		}
+
+		schema.AllOf = append(schema.AllOf, &jsonSchema{AnyOf: alternatives})
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// jsonSchemaType returns the JSON type that values of the type are described
+// as within a JSON Schema
+func jsonSchemaType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Optional values are described by the type of their value
+	if isOptional(t) {
+		return jsonSchemaType(t.Field(0).Type)
+	}
+
+	if t == durationType || isTextUnmarshaler(t) {
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+
+	return "string"
+}
+
+// jsonSchemaValue converts the value into the JSON type, so that defaults and
+// enums match the type of their env. Values that cannot be converted are kept
+// as strings.
+func jsonSchemaValue(jsonType string, value string) interface{} {
+	switch jsonType {
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,89 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestJSONSchema() {
+	type Config struct {
+		Port     int           `tag:"port" desc:"listen port"`
+		Host     string        `tag:"host|hostname,required"`
+		Level    string        `tag:"level,required" enum:"debug,info"`
+		Debug    bool          `tag:"debug" default:"true"`
+		Timeout  time.Duration `tag:"timeout"`
+		Peers    []string      `tag:"peers"`
+		Password string        `tag:"password,secret"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:         "app",
+		TagName:        "tag",
+		DefaultTagName: "default",
+		EnumTagName:    "enum",
+	}
+
+	s.Run("describes each env", func() {
+		schema, err := env.JSONSchema(&Config{Port: 8080, Timeout: time.Second, Password: "hunter2"})
+		s.NoError(err)
+
+		s.JSONEq(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"APP_PORT": {"type": "integer", "description": "listen port", "default": 8080},
+				"APP_HOST": {"type": "string"},
+				"APP_HOSTNAME": {"type": "string"},
+				"APP_LEVEL": {"type": "string", "enum": ["debug", "info"]},
+				"APP_DEBUG": {"type": "boolean", "default": true},
+				"APP_TIMEOUT": {"type": "string", "default": "1s"},
+				"APP_PEERS": {"type": "string"},
+				"APP_PASSWORD": {"type": "string", "writeOnly": true}
+			},
+			"required": ["APP_LEVEL"],
+			"allOf": [
+				{"anyOf": [{"required": ["APP_HOST"]}, {"required": ["APP_HOSTNAME"]}]}
+			]
+		}`, string(schema))
+	})
+}
+
+func (s *EnvstructSuite) TestEnumTagName() {
+	type Config struct {
+		Level    string `tag:"level" enum:"debug, info"`
+		Password string `tag:"password,secret" enum:"a,b"`
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:      "app",
+		TagName:     "tag",
+		EnumTagName: "enum",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("allows the values listed", func() {
+		var config Config
+		err := env.FetchEnvFrom(map[string]string{"APP_LEVEL": "info"}, &config)
+		s.NoError(err)
+		s.Equal("info", config.Level)
+	})
+
+	s.Run("fails on any other value", func() {
+		var config Config
+		err := env.FetchEnvFrom(map[string]string{"APP_LEVEL": "trace"}, &config)
+		s.EqualError(err, "field Level (env APP_LEVEL): value trace is not one of debug, info")
+	})
+
+	s.Run("leaves the values of secrets out of the error", func() {
+		var config Config
+		err := env.FetchEnvFrom(map[string]string{"APP_PASSWORD": "hunter2"}, &config)
+		s.EqualError(err, "field Password (env APP_PASSWORD): value is not one of a, b")
+	})
+}
@@ -0,0 +1,101 @@
+package envstruct
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// KubernetesData returns the current configuration of the struct split into
+// the data of a Kubernetes ConfigMap and a Secret, keyed by the name of each
+// env, so that deployment manifests can be generated from the struct. Fields
+// with the "secret" tag option go into the secret and every other field into
+// the config map. The values are formatted the same way as they would be
+// written in the env and are not base64 encoded, so the secret is meant to be
+// used as the stringData of a Secret. Fields that are nil pointers are left
+// out.
+func (e Envstruct) KubernetesData(object interface{}) (map[string]string, map[string]string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, nil, errors.New("failed to build kubernetes data for object, needs to be type struct")
+	}
+
+	values, err := e.envValues(object)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configMap := map[string]string{}
+	secret := map[string]string{}
+	for _, v := range values {
+		if v.field.options.secret {
+			secret[v.name] = v.value
+		} else {
+			configMap[v.name] = v.value
+		}
+	}
+
+	return configMap, secret, nil
+}
+
+// kubernetesManifest is a ConfigMap or Secret manifest
+type kubernetesManifest struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   kubernetesMeta    `yaml:"metadata"`
+	Type       string            `yaml:"type,omitempty"`
+	Data       map[string]string `yaml:"data"`
+}
+
+// kubernetesMeta is the metadata of a kubernetesManifest
+type kubernetesMeta struct {
+	Name string `yaml:"name"`
+}
+
+// KubernetesManifests renders the KubernetesData of the struct as the YAML
+// manifests of a ConfigMap and a Secret with the given name, separated by
+// "---", which can be applied with kubectl or referenced through envFrom. The
+// values of the secret are base64 encoded as the data of the Secret requires.
+// The Secret is left out if the struct has no secrets.
+func (e Envstruct) KubernetesManifests(object interface{}, name string) (string, error) {
+	configMap, secret, err := e.KubernetesData(object)
+	if err != nil {
+		return "", err
+	}
+
+	manifests := []kubernetesManifest{{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata:   kubernetesMeta{Name: name},
+		Data:       configMap,
+	}}
+
+	if len(secret) > 0 {
+		encoded := map[string]string{}
+		for envName, value := range secret {
+			encoded[envName] = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+
+		manifests = append(manifests, kubernetesManifest{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Metadata:   kubernetesMeta{Name: name},
+			Type:       "Opaque",
+			Data:       encoded,
+		})
+	}
+
+	documents := make([]string, len(manifests))
+	for i, manifest := range manifests {
+		document, err := yaml.Marshal(manifest)
+		if err != nil {
+			return "", err
+		}
+
+		documents[i] = string(document)
+	}
+
+	return strings.Join(documents, "---\n"), nil
+}
@@ -0,0 +1,75 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestKubernetes() {
+	type Config struct {
+		Port     int           `tag:"port"`
+		Timeout  time.Duration `tag:"timeout"`
+		Hosts    []string      `tag:"hosts|peers"`
+		Password string        `tag:"password,secret"`
+		Token    *string       `tag:"token,secret"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+	}
+
+	config := Config{
+		Port:     8080,
+		Timeout:  time.Minute,
+		Hosts:    []string{"a", "b"},
+		Password: "hunter2",
+	}
+
+	s.Run("splits the secrets from the rest of the config", func() {
+		configMap, secret, err := env.KubernetesData(&config)
+		s.NoError(err)
+
+		s.Equal(map[string]string{
+			"APP_PORT":    "8080",
+			"APP_TIMEOUT": "1m0s",
+			"APP_HOSTS":   "a,b",
+		}, configMap)
+		s.Equal(map[string]string{"APP_PASSWORD": "hunter2"}, secret)
+	})
+
+	s.Run("renders the manifests", func() {
+		manifests, err := env.KubernetesManifests(&config, "myapp")
+		s.NoError(err)
+
+		s.Equal(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: myapp
+data:
+  APP_HOSTS: a,b
+  APP_PORT: "8080"
+  APP_TIMEOUT: 1m0s
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: myapp
+type: Opaque
+data:
+  APP_PASSWORD: aHVudGVyMg==
+`, manifests)
+	})
+
+	s.Run("leaves out the secret if there are no secrets", func() {
+		type Config struct {
+			Port int `tag:"port"`
+		}
+
+		manifests, err := env.KubernetesManifests(&Config{Port: 80}, "myapp")
+		s.NoError(err)
+		s.NotContains(manifests, "Secret")
+	})
+}
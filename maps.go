@@ -0,0 +1,224 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// entry is an element of a map of structs, which is set on the map once the
+// fields within it have been applied, as the elements of a map cannot be set
+// in place
+type entry struct {
+	key   reflect.Value
+	value reflect.Value
+}
+
+// isStructMap returns true if the type is a map of structs, or of pointers to
+// structs, whose elements are fetched from envs with the key of the element
+// in their names, for ex. PREFIX_DATABASES_USERS_HOST.
+func isStructMap(t reflect.Type) bool {
+	if t.Kind() != reflect.Map {
+		return false
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	return elem.Kind() == reflect.Struct && !isValueStruct(elem)
+}
+
+// extractMap will extract the fields of each element of the map of structs,
+// with the key of the element added onto the env names and the path of its
+// fields. When fetching, the keys are found by listing the names of the envs
+// set within the sources, and a field is added for the map itself so that it
+// is replaced by a copy holding the existing and new elements once the plan is
+// applied. Otherwise the fields are extracted from copies of the existing
+// elements.
+func (e Envstruct) extractMap(fields *[]*field, envNameBuilders [][]string, path []string, index []int, mapValue reflect.Value) error {
+	mapType := mapValue.Type()
+
+	// Existing keys are matched up with the keys found within the env names
+	// through the segment that they add onto the env names
+	keys := map[string]reflect.Value{}
+	for _, key := range mapValue.MapKeys() {
+		keys[BuildName([]string{fmt.Sprint(key.Interface())})] = key
+	}
+
+	if e.growCollections {
+		found, err := e.mapKeys(envNameBuilders, mapType.Elem())
+		if err != nil {
+			return err
+		}
+
+		for _, segment := range found {
+			if _, ok := keys[segment]; ok {
+				continue
+			}
+
+			key, err := e.parseKey(mapType.Key(), segment)
+			if err != nil {
+				return fmt.Errorf("failed to parse key %s of field %s: %w", segment, strings.Join(path, "."), err)
+			}
+
+			keys[segment] = key
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	segments := make([]string, 0, len(keys))
+	for segment := range keys {
+		segments = append(segments, segment)
+	}
+
+	sort.Strings(segments)
+
+	var entries []entry
+	var errs Errors
+	for _, segment := range segments {
+		key := keys[segment]
+
+		element := reflect.New(mapType.Elem()).Elem()
+		if existing := mapValue.MapIndex(key); existing.IsValid() {
+			element.Set(existing)
+		}
+
+		if element.Kind() == reflect.Ptr {
+			if element.IsNil() {
+				if !e.growCollections {
+					continue
+				}
+
+				element.Set(reflect.New(element.Type().Elem()))
+			}
+		}
+
+		entries = append(entries, entry{key: key, value: element})
+
+		if element.Kind() == reflect.Ptr {
+			element = element.Elem()
+		}
+
+		err := e.extractStruct(fields, appendAliases(envNameBuilders, []string{segment}), append(path[:len(path):len(path)], fmt.Sprint(key.Interface())), index, element)
+		if err != nil {
+			if !e.BestEffort {
+				return err
+			}
+
+			errs = errs.collect(err)
+		}
+	}
+
+	if e.growCollections {
+		*fields = append(*fields, &field{
+			path:     strings.Join(path, "."),
+			index:    index,
+			elements: reflect.MakeMapWithSize(mapType, len(entries)),
+			entries:  entries,
+			value:    mapValue,
+		})
+	}
+
+	return errs.errorOrNil()
+}
+
+// mapKeys returns the keys of the elements of a map of structs that have envs
+// set within the sources, as they are written within the env names. An env
+// belongs to an element if it starts with the env name of the map and ends
+// with the env name of one of the fields of the element, with the key in
+// between, for ex. PREFIX_DATABASES_USERS_DB_HOST has the key USERS_DB when
+// the element has a field with the env name HOST. The longest env name of a
+// field that matches is used, so that the key does not swallow part of it.
+func (e Envstruct) mapKeys(envNameBuilders [][]string, elemType reflect.Type) ([]string, error) {
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	// The env names of the fields of the element, relative to the element
+	probeEnv := e
+	probeEnv.growCollections = false
+
+	var probe []*field
+	err := probeEnv.extractStruct(&probe, [][]string{nil}, nil, nil, reflect.New(elemType).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	var suffixes []string
+	for _, f := range probe {
+		for _, envName := range f.envNames {
+			suffixes = append(suffixes, e.nameSeparator()+envName)
+		}
+	}
+
+	sort.SliceStable(suffixes, func(i, j int) bool { return len(suffixes[i]) > len(suffixes[j]) })
+
+	var prefixes []string
+	for _, envNameBuilder := range envNameBuilders {
+		prefix := e.buildName(envNameBuilder)
+		if prefix != "" {
+			prefix += e.nameSeparator()
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	found := map[string]bool{}
+	for _, name := range e.listNames() {
+		for _, prefix := range prefixes {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+
+			rest := strings.TrimPrefix(name, prefix)
+			for _, suffix := range suffixes {
+				if len(rest) > len(suffix) && strings.HasSuffix(rest, suffix) {
+					found[strings.TrimSuffix(rest, suffix)] = true
+					break
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// parseKey parses the key of an element of a map as it is written within the
+// env names into the key type of the map
+func (e Envstruct) parseKey(keyType reflect.Type, key string) (reflect.Value, error) {
+	if keyType.Kind() == reflect.String {
+		return reflect.ValueOf(key).Convert(keyType), nil
+	}
+
+	return e.Parser.ParseValue(keyType, key)
+}
+
+// listNames returns the names of every env that is set within the sources
+// that can list them
+func (e Envstruct) listNames() []string {
+	var names []string
+	for _, source := range e.sources() {
+		if listable, ok := source.(ListableSource); ok {
+			for _, name := range listable.Names() {
+				if value, found := listable.Lookup(name); found && value != "" {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,95 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestStructMaps() {
+	type Database struct {
+		Host   string `tag:"host"`
+		Port   int    `tag:"port"`
+		DBHost string `tag:"db_host"`
+	}
+
+	type Config struct {
+		Databases map[string]Database  `tag:"databases"`
+		Replicas  map[string]*Database `tag:"replicas"`
+		Shards    map[int]Database     `tag:"shards"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("uses the name within the envs as the key", func() {
+		os.Clearenv()
+		os.Setenv("APP_DATABASES_USERS_HOST", "a")
+		os.Setenv("APP_DATABASES_USERS_PORT", "1")
+		os.Setenv("APP_DATABASES_BILLING_EU_HOST", "b")
+		os.Setenv("APP_DATABASES_ORDERS_DB_HOST", "c")
+		os.Setenv("APP_REPLICAS_USERS_PORT", "2")
+		os.Setenv("APP_SHARDS_3_HOST", "d")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Databases: map[string]Database{
+				"USERS":      {Host: "a", Port: 1},
+				"BILLING_EU": {Host: "b"},
+				"ORDERS":     {DBHost: "c"},
+			},
+			Replicas: map[string]*Database{"USERS": {Port: 2}},
+			Shards:   map[int]Database{3: {Host: "d"}},
+		}, config)
+	})
+
+	s.Run("keeps the defaults of existing elements", func() {
+		os.Clearenv()
+		os.Setenv("APP_DATABASES_USERS_HOST", "a")
+
+		config := Config{Databases: map[string]Database{
+			"users":  {Host: "default", Port: 5432},
+			"orders": {Host: "orders"},
+		}}
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(map[string]Database{
+			"users":  {Host: "a", Port: 5432},
+			"orders": {Host: "orders"},
+		}, config.Databases)
+	})
+
+	s.Run("fails on keys that cannot be parsed", func() {
+		os.Clearenv()
+		os.Setenv("APP_SHARDS_ONE_HOST", "a")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "failed to parse key ONE of field Shards: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `ONE` into int")
+		s.Nil(config.Shards)
+	})
+
+	s.Run("finds keys within the listable sources", func() {
+		os.Clearenv()
+
+		var config Config
+		err := env.FetchEnvFrom(map[string]string{"APP_DATABASES_USERS_HOST": "a"}, &config)
+		s.NoError(err)
+
+		s.Equal(map[string]Database{"USERS": {Host: "a"}}, config.Databases)
+	})
+}
@@ -0,0 +1,89 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// markdownEscaper escapes the characters that would otherwise break out of a
+// cell of a Markdown table
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// Markdown renders a Markdown table describing every env that the struct is
+// fetched from, so that the docs of an application can be generated from its
+// config struct and kept in sync with it. Each env is listed with the type of
+// its field, its default, whether it is required and its description, with
+// the names built the same way as FetchEnv does, for ex.
+//
+//	| Name | Type | Default | Required | Description |
+//	| --- | --- | --- | --- | --- |
+//	| `APP_PORT` | int | `8080` | no | listen port |
+//
+// The value already set on a field is used as its default, unless it has one
+// within the DefaultTagName. The default of a secret is redacted.
+func (e Envstruct) Markdown(object interface{}) (string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return "", errors.New("failed to render markdown for object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return "", err
+	}
+
+	var table strings.Builder
+	table.WriteString("| Name | Type | Default | Required | Description |\n")
+	table.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, f := range fields {
+		if !f.tagged || len(f.envNames) == 0 {
+			continue
+		}
+
+		spec := e.fieldSpec(f)
+
+		names := make([]string, len(spec.EnvNames))
+		for i, envName := range spec.EnvNames {
+			names[i] = markdownCode(envName)
+		}
+
+		var defaultValue string
+		if spec.Default != "" {
+			defaultValue = markdownCode(spec.Default)
+		}
+
+		required := "no"
+		if spec.Required {
+			required = "yes"
+		}
+
+		fmt.Fprintf(&table, "| %s | %s | %s | %s | %s |\n",
+			strings.Join(names, ", "),
+			markdownEscaper.Replace(typeName(f.description.Type)),
+			defaultValue,
+			required,
+			markdownEscaper.Replace(spec.Description),
+		)
+	}
+
+	return table.String(), nil
+}
+
+// markdownCode wraps the value in a code span, using a longer run of
+// backticks than any within the value so that it cannot be closed early
+func markdownCode(value string) string {
+	fence := "`"
+	for strings.Contains(value, fence) {
+		fence += "`"
+	}
+
+	// Pipes end the cell of a table even within a code span
+	value = markdownEscaper.Replace(value)
+	if strings.HasPrefix(value, "`") || strings.HasSuffix(value, "`") {
+		value = " " + value + " "
+	}
+
+	return fence + value + fence
+}
@@ -0,0 +1,52 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestMarkdown() {
+	type Config struct {
+		Port     int           `tag:"port" desc:"listen port"`
+		Host     string        `tag:"host|hostname,required" desc:"listen address"`
+		Timeout  time.Duration `tag:"timeout" default:"30s"`
+		Password string        `tag:"password,secret" desc:"admin password"`
+		Mode     string        `tag:"mode" override:"MODE" desc:"one of a|b"`
+		Database struct {
+			Name string `tag:"name"`
+		} `tag:"db"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:         "app",
+		TagName:        "tag",
+		OverrideName:   "override",
+		DefaultTagName: "default",
+	}
+
+	s.Run("renders a table of each env", func() {
+		markdown, err := env.Markdown(&Config{Port: 8080, Password: "hunter2"})
+		s.NoError(err)
+
+		s.Equal("| Name | Type | Default | Required | Description |\n"+
+			"| --- | --- | --- | --- | --- |\n"+
+			"| `APP_PORT` | int | `8080` | no | listen port |\n"+
+			"| `APP_HOST`, `APP_HOSTNAME` | string |  | yes | listen address |\n"+
+			"| `APP_TIMEOUT` | duration | `30s` | no |  |\n"+
+			"| `APP_PASSWORD` | string | `******` | no | admin password |\n"+
+			"| `MODE` | string |  | no | one of a\\|b |\n"+
+			"| `APP_DB_NAME` | string |  | no |  |\n", markdown)
+	})
+
+	s.Run("keeps backticks within code spans", func() {
+		type Config struct {
+			Format string `tag:"format"`
+		}
+
+		markdown, err := env.Markdown(&Config{Format: "`date`"})
+		s.NoError(err)
+		s.Contains(markdown, "| `APP_FORMAT` | string | `` `date` `` | no |  |\n")
+	})
+}
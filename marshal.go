@@ -0,0 +1,71 @@
+package envstruct
+
+import (
+	"errors"
+	"reflect"
+)
+
+// envValue is the value of a field formatted the same way as it would be
+// written in its env
+type envValue struct {
+	// name is the name of the env with the highest precedence of the field
+	name string
+
+	// value is the formatted value of the field
+	value string
+
+	// field is the field that the value is of
+	field *field
+}
+
+// MarshalEnv is the inverse of FetchEnv. It walks through the struct, building
+// the names of the envs with the same naming rules, and returns the current
+// value of every field keyed by the name of its env, so that for ex. the
+// configuration can be passed on to child processes. The values are formatted
+// the same way as they would be written in the env, with the elements of
+// slices and maps joined with the delimiter of the field. If a field can be
+// fetched from multiple envs, the env with the highest precedence is used.
+// Secrets are included as they are, and fields that are nil pointers are left
+// out.
+func (e Envstruct) MarshalEnv(object interface{}) (map[string]string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to marshal object, needs to be type struct")
+	}
+
+	values, err := e.envValues(object)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	for _, v := range values {
+		env[v.name] = v.value
+	}
+
+	return env, nil
+}
+
+// envValues returns the formatted value of every field that has an env, in
+// the order that the fields are declared. Fields that are nil pointers are
+// left out.
+func (e Envstruct) envValues(object interface{}) ([]envValue, error) {
+	fields, err := e.fields(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []envValue
+	for _, f := range fields {
+		if !f.tagged || len(f.envNames) == 0 || dumpValue(f.value, false) == nil {
+			continue
+		}
+
+		values = append(values, envValue{
+			name:  f.envNames[0],
+			value: formatValue(f.value, e.fieldParser(f.options)),
+			field: f,
+		})
+	}
+
+	return values, nil
+}
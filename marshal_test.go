@@ -0,0 +1,79 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestMarshalEnv() {
+	type Config struct {
+		Host     string              `tag:"host|hostname"`
+		Port     int                 `tag:"port"`
+		Timeout  time.Duration       `tag:"timeout"`
+		Peers    []string            `tag:"peers"`
+		Paths    []string            `tag:"paths,delim=:"`
+		Limits   map[string]int      `tag:"limits"`
+		Routes   map[string][]string `tag:"routes"`
+		Password string              `tag:"password,secret"`
+		Token    *string             `tag:"token"`
+		Database struct {
+			Name string `tag:"name"`
+		} `tag:"db"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	config := Config{
+		Host:     "localhost",
+		Port:     8080,
+		Timeout:  time.Minute,
+		Peers:    []string{"a", "b,c"},
+		Paths:    []string{"/usr/bin", "/bin"},
+		Limits:   map[string]int{"b": 2, "a": 1},
+		Routes:   map[string][]string{"users": {"GET", "POST"}, "orders": {"GET"}},
+		Password: "hunter2",
+		Internal: "internal",
+	}
+	config.Database.Name = "app"
+
+	s.Run("returns the value of each env", func() {
+		values, err := env.MarshalEnv(&config)
+		s.NoError(err)
+
+		s.Equal(map[string]string{
+			"APP_HOST":     "localhost",
+			"APP_PORT":     "8080",
+			"APP_TIMEOUT":  "1m0s",
+			"APP_PEERS":    `a,"b,c"`,
+			"APP_PATHS":    "/usr/bin:/bin",
+			"APP_LIMITS":   "a:1,b:2",
+			"APP_ROUTES":   "orders:GET;users:GET,POST",
+			"APP_PASSWORD": "hunter2",
+			"APP_DB_NAME":  "app",
+		}, values)
+	})
+
+	s.Run("can be fetched back into the struct", func() {
+		values, err := env.MarshalEnv(&config)
+		s.NoError(err)
+
+		var fetched Config
+		err = env.FetchEnvFrom(values, &fetched)
+		s.NoError(err)
+
+		expected := config
+		expected.Internal = ""
+		s.Equal(expected, fetched)
+	})
+}
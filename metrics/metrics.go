@@ -0,0 +1,102 @@
+// Package metrics exposes Prometheus collectors for envstruct, so that the
+// health of config loading shows up on existing dashboards.
+//
+// The Collector is both a prometheus.Collector and an envstruct.Observer, so
+// it is registered with Prometheus and set as the Observer on the Envstruct:
+//
+//	collector := metrics.New("myapp")
+//	prometheus.MustRegister(collector)
+//
+//	env := envstruct.Envstruct{
+//		TagName:  "env",
+//		Observer: collector,
+//		...
+//	}
+package metrics
+
+import (
+	"github.com/clarafu/envstruct"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector collects metrics about each time a struct is populated by
+// envstruct.
+type Collector struct {
+	lastLoad   prometheus.Gauge
+	duration   prometheus.Histogram
+	loads      prometheus.Counter
+	errors     prometheus.Counter
+	sourceHits *prometheus.CounterVec
+}
+
+// New creates a Collector with the metrics under the given namespace, for ex.
+// "myapp" results in metrics such as "myapp_envstruct_loads_total".
+func New(namespace string) *Collector {
+	return &Collector{
+		lastLoad: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "envstruct",
+			Name:      "last_load_timestamp_seconds",
+			Help:      "Unix timestamp of the last time the config was successfully loaded.",
+		}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "envstruct",
+			Name:      "load_duration_seconds",
+			Help:      "Time taken to load the config.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+		}),
+		loads: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "envstruct",
+			Name:      "loads_total",
+			Help:      "Number of times the config has been loaded.",
+		}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "envstruct",
+			Name:      "load_errors_total",
+			Help:      "Number of times loading the config failed.",
+		}),
+		sourceHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "envstruct",
+			Name:      "source_hits_total",
+			Help:      "Number of fields populated from each source.",
+		}, []string{"source"}),
+	}
+}
+
+// ObserveFetch implements envstruct.Observer.
+func (c *Collector) ObserveFetch(event envstruct.FetchEvent) {
+	c.loads.Inc()
+	c.duration.Observe(event.Duration.Seconds())
+
+	if event.Err != nil {
+		c.errors.Inc()
+	} else {
+		c.lastLoad.Set(float64(event.Time.Add(event.Duration).UnixNano()) / 1e9)
+	}
+
+	for source, hits := range event.Sources {
+		c.sourceHits.WithLabelValues(source).Add(float64(hits))
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.lastLoad.Describe(ch)
+	c.duration.Describe(ch)
+	c.loads.Describe(ch)
+	c.errors.Describe(ch)
+	c.sourceHits.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.lastLoad.Collect(ch)
+	c.duration.Collect(ch)
+	c.loads.Collect(ch)
+	c.errors.Collect(ch)
+	c.sourceHits.Collect(ch)
+}
@@ -0,0 +1,56 @@
+package metrics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"github.com/clarafu/envstruct/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	collector := metrics.New("test")
+
+	loaded := time.Unix(1700000000, 0)
+	collector.ObserveFetch(envstruct.FetchEvent{
+		Time:     loaded,
+		Duration: time.Second,
+		Sources: map[string]int{
+			envstruct.SourceEnv:      3,
+			envstruct.SourceOverride: 1,
+		},
+	})
+	collector.ObserveFetch(envstruct.FetchEvent{
+		Time:     loaded.Add(time.Minute),
+		Duration: time.Second,
+		Err:      errors.New("failed"),
+	})
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP test_envstruct_last_load_timestamp_seconds Unix timestamp of the last time the config was successfully loaded.
+# TYPE test_envstruct_last_load_timestamp_seconds gauge
+test_envstruct_last_load_timestamp_seconds 1.700000001e+09
+# HELP test_envstruct_load_errors_total Number of times loading the config failed.
+# TYPE test_envstruct_load_errors_total counter
+test_envstruct_load_errors_total 1
+# HELP test_envstruct_loads_total Number of times the config has been loaded.
+# TYPE test_envstruct_loads_total counter
+test_envstruct_loads_total 2
+# HELP test_envstruct_source_hits_total Number of fields populated from each source.
+# TYPE test_envstruct_source_hits_total counter
+test_envstruct_source_hits_total{source="env"} 3
+test_envstruct_source_hits_total{source="override"} 1
+`),
+		"test_envstruct_last_load_timestamp_seconds",
+		"test_envstruct_load_errors_total",
+		"test_envstruct_loads_total",
+		"test_envstruct_source_hits_total",
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, testutil.CollectAndCount(collector, "test_envstruct_load_duration_seconds"))
+}
@@ -0,0 +1,32 @@
+package envstruct
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts the name of a field from CamelCase into SNAKE_CASE, which
+// is how env names are derived from field names when DeriveNames is on. A new
+// word starts at each uppercase letter that follows a lowercase letter or a
+// digit, and at the last uppercase letter of an acronym that is followed by a
+// lowercase letter, for ex. "MaxIdleConns" becomes "MAX_IDLE_CONNS" and
+// "HTTPServer" becomes "HTTP_SERVER".
+func SnakeCase(name string) string {
+	runes := []rune(name)
+
+	var snake strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				snake.WriteRune('_')
+			}
+		}
+
+		snake.WriteRune(unicode.ToUpper(r))
+	}
+
+	return snake.String()
+}
@@ -0,0 +1,169 @@
+package envstruct_test
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestSnakeCase() {
+	for name, expected := range map[string]string{
+		"Host":         "HOST",
+		"MaxIdleConns": "MAX_IDLE_CONNS",
+		"HTTPServer":   "HTTP_SERVER",
+		"DBHost":       "DB_HOST",
+		"URL":          "URL",
+		"Port2":        "PORT2",
+		"V2Endpoint":   "V2_ENDPOINT",
+		"already_set":  "ALREADY_SET",
+	} {
+		s.Equal(expected, envstruct.SnakeCase(name), name)
+	}
+}
+
+func (s *EnvstructSuite) TestDeriveNames() {
+	type Database struct {
+		Host         string
+		MaxIdleConns int
+	}
+
+	type Config struct {
+		Timeout  time.Duration
+		Database Database
+		Cache    Database `tag:"redis"`
+		Base
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:      "app",
+		TagName:     "tag",
+		DeriveNames: true,
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	os.Setenv("APP_TIMEOUT", "5s")
+	os.Setenv("APP_DATABASE_HOST", "db")
+	os.Setenv("APP_DATABASE_MAX_IDLE_CONNS", "10")
+	os.Setenv("APP_REDIS_HOST", "redis")
+	os.Setenv("APP_PORT", "8080")
+
+	var config Config
+	err := env.FetchEnv(&config)
+	s.NoError(err)
+
+	s.Equal(Config{
+		Timeout:  5 * time.Second,
+		Database: Database{Host: "db", MaxIdleConns: 10},
+		Cache:    Database{Host: "redis"},
+		Base:     Base{Port: 8080},
+	}, config)
+}
+
+func (s *EnvstructSuite) TestNameFunc() {
+	type Config struct {
+		Database struct {
+			MaxConns int `tag:"max-conns"`
+		} `tag:"database"`
+		Timeout int `tag:"timeout" legacy:"OLD_TIMEOUT"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		// Nested structs are separated by double underscores, dashes become
+		// underscores and legacy names are accepted after the new ones
+		NameFunc: func(path []string, field reflect.StructField) []string {
+			for i, segment := range path {
+				path[i] = strings.ToUpper(strings.ReplaceAll(segment, "-", "_"))
+			}
+
+			names := []string{strings.Join(path, "__")}
+			if legacy, ok := field.Tag.Lookup("legacy"); ok {
+				names = append(names, legacy)
+			}
+
+			return names
+		},
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	os.Setenv("APP__DATABASE__MAX_CONNS", "10")
+	os.Setenv("OLD_TIMEOUT", "30")
+
+	var config Config
+	err := env.FetchEnv(&config)
+	s.NoError(err)
+
+	s.Equal(10, config.Database.MaxConns)
+	s.Equal(30, config.Timeout)
+
+	names, err := env.EnvNames(&config)
+	s.NoError(err)
+	s.Equal([]string{"APP__DATABASE__MAX_CONNS", "APP__TIMEOUT", "OLD_TIMEOUT"}, names)
+
+	// Fields that the NameFunc returns no names for are not fetched from the
+	// env, or dumped
+	env.NameFunc = func(path []string, field reflect.StructField) []string {
+		return nil
+	}
+
+	dump, err := env.Dump(&config)
+	s.NoError(err)
+	s.Empty(dump)
+}
+
+func (s *EnvstructSuite) TestNameSeparator() {
+	type Database struct {
+		MaxConns int `tag:"max_conns"`
+	}
+
+	type Config struct {
+		Database  Database            `tag:"db"`
+		Replicas  []Database          `tag:"replicas"`
+		Databases map[string]Database `tag:"databases"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:        "app",
+		TagName:       "tag",
+		NameSeparator: "__",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	os.Setenv("APP__DB__MAX_CONNS", "10")
+	os.Setenv("APP__REPLICAS__0__MAX_CONNS", "20")
+	os.Setenv("APP__DATABASES__USERS_EU__MAX_CONNS", "30")
+
+	var config Config
+	err := env.FetchEnv(&config)
+	s.NoError(err)
+
+	s.Equal(Config{
+		Database:  Database{MaxConns: 10},
+		Replicas:  []Database{{MaxConns: 20}},
+		Databases: map[string]Database{"USERS_EU": {MaxConns: 30}},
+	}, config)
+}
@@ -0,0 +1,78 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestNestedCollections() {
+	type Config struct {
+		Matrix [][]int             `tag:"matrix"`
+		Routes map[string][]string `tag:"routes"`
+		Groups []map[string]int    `tag:"groups"`
+		Cube   [][][]int           `tag:"cube"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("splits on the outer delimiter first", func() {
+		os.Clearenv()
+		os.Setenv("APP_MATRIX", "1,2;3,4")
+		os.Setenv("APP_ROUTES", "users:GET,POST;orders:GET")
+		os.Setenv("APP_GROUPS", "a:1,b:2;c:3")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Matrix: [][]int{{1, 2}, {3, 4}},
+			Routes: map[string][]string{"users": {"GET", "POST"}, "orders": {"GET"}},
+			Groups: []map[string]int{{"a": 1, "b": 2}, {"c": 3}},
+		}, config)
+	})
+
+	s.Run("uses the configured outer delimiter", func() {
+		os.Clearenv()
+		os.Setenv("APP_MATRIX", "1,2|3")
+
+		env := env
+		env.Parser.OuterDelimiter = "|"
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal([][]int{{1, 2}, {3}}, config.Matrix)
+	})
+
+	s.Run("names the element that failed", func() {
+		os.Clearenv()
+		os.Setenv("APP_MATRIX", "1,2;3,abc")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field Matrix (env APP_MATRIX): element 1: element 1: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `abc` into int")
+	})
+
+	s.Run("only supports one level of nesting", func() {
+		os.Clearenv()
+		os.Setenv("APP_CUBE", "1,2;3,4")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field Cube (env APP_CUBE): element 0: slices and maps can only be nested one level deep")
+	})
+}
@@ -0,0 +1,147 @@
+package envstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// SourceObject is the name of the Object Source
+const SourceObject = "object"
+
+// ErrObjectNotModified is returned by an ObjectClient when the ETag of the
+// object still matches, so that it is not downloaded again
+var ErrObjectNotModified = errors.New("object not modified")
+
+// ObjectClient is the part of an S3 or GCS client that the Object Source
+// needs, so that envstruct does not depend on either SDK. The client is
+// responsible for authenticating, for ex. with the IAM role of the instance.
+//
+// GetObject returns the contents and ETag of the object. If the etag passed
+// in is not empty and still matches the object, ErrObjectNotModified is
+// returned instead, for ex. by sending it as the If-None-Match header.
+type ObjectClient interface {
+	GetObject(bucket string, key string, etag string) (contents []byte, newETag string, err error)
+}
+
+// Object is a Source for a config bundle stored as an object within S3 or
+// GCS, a common way of distributing the configuration of each environment.
+// The format of the object is chosen by the extension of its key. Objects
+// ending with ".env" are read as .env files, ".yaml" and ".yml" objects are
+// unmarshaled with the Unmarshal func, and anything else is read as JSON. JSON
+// and YAML objects are flattened the same way as a JSONFile.
+//
+// The object is downloaded again on Reload, so a Watcher picks up any
+// changes, unless its ETag is unchanged.
+type Object struct {
+	// Client is the client used to download the object
+	Client ObjectClient
+
+	// Bucket and Key are the location of the object
+	Bucket string
+	Key    string
+
+	// Unmarshal is used for YAML objects, for ex. yaml.Unmarshal
+	Unmarshal UnmarshalFunc
+
+	mu     sync.RWMutex
+	values map[string]string
+	etag   string
+}
+
+// ReadObject downloads the object from the bucket. The unmarshal func is only
+// needed for YAML objects.
+func ReadObject(client ObjectClient, bucket string, key string, unmarshal UnmarshalFunc) (*Object, error) {
+	object := &Object{
+		Client:    client,
+		Bucket:    bucket,
+		Key:       key,
+		Unmarshal: unmarshal,
+	}
+
+	err := object.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// Name returns SourceObject
+func (o *Object) Name() string { return SourceObject }
+
+// Lookup returns the value of the env within the object
+func (o *Object) Lookup(envName string) (string, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	value, found := o.values[envName]
+	return value, found
+}
+
+// Files returns nothing as the object is not read from a file
+func (o *Object) Files() []string { return nil }
+
+// Reload downloads the object again if it has changed. If it fails, the object
+// downloaded before is kept.
+func (o *Object) Reload() error {
+	o.mu.RLock()
+	etag := o.etag
+	o.mu.RUnlock()
+
+	location := o.Bucket + "/" + o.Key
+
+	contents, newETag, err := o.Client.GetObject(o.Bucket, o.Key, etag)
+	if errors.Is(err, ErrObjectNotModified) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to download config object %s: %w", location, err)
+	}
+
+	values, err := o.parse(contents)
+	if err != nil {
+		return fmt.Errorf("failed to parse config object %s: %w", location, err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.values = values
+	o.etag = newETag
+
+	return nil
+}
+
+// parse reads the values out of the object based on the extension of its key
+func (o *Object) parse(contents []byte) (map[string]string, error) {
+	extension := strings.ToLower(path.Ext(o.Key))
+	if extension == ".env" || path.Base(o.Key) == ".env" {
+		return parseDotenv(bytes.NewReader(contents), o.Key)
+	}
+
+	unmarshal := json.Unmarshal
+	if extension == ".yaml" || extension == ".yml" {
+		if o.Unmarshal == nil {
+			return nil, errors.New("an Unmarshal func is needed to read YAML")
+		}
+
+		unmarshal = o.Unmarshal
+	}
+
+	var parsed map[string]interface{}
+	err := unmarshal(contents, &parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	flatten(values, "", parsed)
+
+	return values, nil
+}
@@ -0,0 +1,110 @@
+package envstruct_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+// fakeObjectClient serves objects from memory, using the number of times an
+// object was written as its ETag
+type fakeObjectClient struct {
+	objects   map[string][]byte
+	versions  map[string]int
+	downloads int
+}
+
+func (f *fakeObjectClient) put(bucket string, key string, contents string) {
+	f.objects[bucket+"/"+key] = []byte(contents)
+	f.versions[bucket+"/"+key]++
+}
+
+func (f *fakeObjectClient) GetObject(bucket string, key string, etag string) ([]byte, string, error) {
+	contents, found := f.objects[bucket+"/"+key]
+	if !found {
+		return nil, "", errors.New("NoSuchKey")
+	}
+
+	current := fmt.Sprint(f.versions[bucket+"/"+key])
+	if etag == current {
+		return nil, "", envstruct.ErrObjectNotModified
+	}
+
+	f.downloads++
+	return contents, current, nil
+}
+
+func (s *EnvstructSuite) TestObject() {
+	type Config struct {
+		Database struct {
+			Host string `tag:"host"`
+			Port int    `tag:"port"`
+		} `tag:"db"`
+		Debug bool `tag:"debug"`
+	}
+
+	defer os.Clearenv()
+
+	client := &fakeObjectClient{objects: map[string][]byte{}, versions: map[string]int{}}
+	client.put("config", "prod/app.json", `{"db": {"host": "db.internal", "port": 5432}}`)
+	client.put("config", "prod/app.yml", "db:\n  host: db.internal\ndebug: true\n")
+	client.put("config", "prod/app.env", "DB_HOST=db.internal\nDB_PORT=5432\n")
+
+	fetch := func(object *envstruct.Object) Config {
+		env := envstruct.Envstruct{
+			TagName: "tag",
+			Sources: []envstruct.Source{object},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		return config
+	}
+
+	s.Run("reads the object based on its extension", func() {
+		object, err := envstruct.ReadObject(client, "config", "prod/app.json", nil)
+		s.NoError(err)
+		s.Equal("db.internal", fetch(object).Database.Host)
+		s.Equal(5432, fetch(object).Database.Port)
+
+		object, err = envstruct.ReadObject(client, "config", "prod/app.yml", yaml.Unmarshal)
+		s.NoError(err)
+		s.Equal("db.internal", fetch(object).Database.Host)
+		s.True(fetch(object).Debug)
+
+		object, err = envstruct.ReadObject(client, "config", "prod/app.env", nil)
+		s.NoError(err)
+		s.Equal(5432, fetch(object).Database.Port)
+	})
+
+	s.Run("only downloads the object again when it changed", func() {
+		object, err := envstruct.ReadObject(client, "config", "prod/app.json", nil)
+		s.NoError(err)
+
+		downloads := client.downloads
+		s.NoError(object.Reload())
+		s.Equal(downloads, client.downloads)
+
+		client.put("config", "prod/app.json", `{"db": {"host": "db2.internal"}}`)
+		s.NoError(object.Reload())
+		s.Equal(downloads+1, client.downloads)
+		s.Equal("db2.internal", fetch(object).Database.Host)
+	})
+
+	s.Run("fails to read YAML without an unmarshal func", func() {
+		_, err := envstruct.ReadObject(client, "config", "prod/app.yml", nil)
+		s.EqualError(err, "failed to parse config object config/prod/app.yml: an Unmarshal func is needed to read YAML")
+	})
+
+	s.Run("fails if the object can not be downloaded", func() {
+		_, err := envstruct.ReadObject(client, "config", "missing.json", nil)
+		s.EqualError(err, "failed to download config object config/missing.json: NoSuchKey")
+	})
+}
@@ -0,0 +1,64 @@
+package envstruct
+
+import "time"
+
+const (
+	// SourceEnv is the source of values fetched from the environment
+	SourceEnv = "env"
+
+	// SourceOverride is the source of values set through the Overrides
+	SourceOverride = "override"
+
+	// SourcePrompt is the source of values entered through the Prompter
+	SourcePrompt = "prompt"
+
+	// SourceDefault is the source of values set through the DefaultTagName
+	SourceDefault = "default"
+)
+
+// Observer can be set on the Envstruct to be notified every time a struct is
+// populated, for ex. to export metrics about config loading.
+type Observer interface {
+	ObserveFetch(event FetchEvent)
+}
+
+// FetchEvent describes a single call to populate a struct.
+type FetchEvent struct {
+	// Time is when the fetch started
+	Time time.Time
+
+	// Duration is how long the fetch took
+	Duration time.Duration
+
+	// Err is the error returned by the fetch, if any
+	Err error
+
+	// Sources is the number of fields that were populated from each source,
+	// keyed by the name of the source (for ex. SourceEnv)
+	Sources map[string]int
+}
+
+// observe will notify the Observer (if set) about the fetch
+func (e Envstruct) observe(start time.Time, p *plan, err error) {
+	if e.Observer == nil {
+		return
+	}
+
+	sources := map[string]int{}
+	if p != nil {
+		for _, a := range p.assignments {
+			if a.source == "" {
+				continue
+			}
+
+			sources[a.source]++
+		}
+	}
+
+	e.Observer.ObserveFetch(FetchEvent{
+		Time:     start,
+		Duration: time.Since(start),
+		Err:      err,
+		Sources:  sources,
+	})
+}
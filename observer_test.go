@@ -0,0 +1,104 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type recordingObserver struct {
+	events []envstruct.FetchEvent
+}
+
+func (o *recordingObserver) ObserveFetch(event envstruct.FetchEvent) {
+	o.events = append(o.events, event)
+}
+
+func (s *EnvstructSuite) TestObserver() {
+	type Config struct {
+		Field1 string `tag:"field1"`
+		Field2 string `tag:"field2"`
+		Field3 int    `tag:"field3"`
+	}
+
+	defer os.Clearenv()
+
+	s.Run("observes the sources of each populated field", func() {
+		observer := &recordingObserver{}
+		env := envstruct.Envstruct{
+			Prefix:    "prefix",
+			TagName:   "tag",
+			Overrides: map[string]string{"Field2": "pinned"},
+			Observer:  observer,
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		os.Setenv("PREFIX_FIELD1", "value")
+		os.Setenv("PREFIX_FIELD3", "3")
+
+		err := env.FetchEnv(&Config{})
+		s.NoError(err)
+
+		s.Len(observer.events, 1)
+		s.NoError(observer.events[0].Err)
+		s.False(observer.events[0].Time.IsZero())
+		s.Equal(map[string]int{
+			envstruct.SourceEnv:      2,
+			envstruct.SourceOverride: 1,
+		}, observer.events[0].Sources)
+	})
+
+	s.Run("observes failed fetches", func() {
+		observer := &recordingObserver{}
+		env := envstruct.Envstruct{
+			Prefix:   "prefix",
+			TagName:  "tag",
+			Observer: observer,
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		os.Setenv("PREFIX_FIELD3", "notanint")
+
+		err := env.FetchEnv(&Config{})
+		s.Error(err)
+
+		s.Len(observer.events, 1)
+		s.Equal(err, observer.events[0].Err)
+		s.Empty(observer.events[0].Sources)
+	})
+
+	s.Run("observes sources that fail to reload within a Watcher", func() {
+		path := filepath.Join(s.T().TempDir(), ".env")
+		s.NoError(os.WriteFile(path, []byte("PREFIX_FIELD1=value\n"), 0600))
+
+		file, err := envstruct.ReadDotenvFile(path)
+		s.NoError(err)
+
+		observer := &recordingObserver{}
+		env := envstruct.Envstruct{
+			Prefix:   "prefix",
+			TagName:  "tag",
+			Sources:  []envstruct.Source{file},
+			Observer: observer,
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		os.Clearenv()
+
+		watcher, err := envstruct.NewWatcher(env, &Config{})
+		s.NoError(err)
+
+		s.NoError(os.Remove(path))
+
+		_, err = watcher.Reload()
+		s.Error(err)
+
+		s.Len(observer.events, 2)
+		s.Equal(err, observer.events[1].Err)
+	})
+}
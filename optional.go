@@ -0,0 +1,80 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Optional is a field type that records whether its env was set, giving
+// presence semantics without resorting to pointers. Value holds the parsed
+// value, Raw the value as it was fetched and Present is true if the env was
+// set. Fields of type Optional are never required, even if RequireAll is on.
+//
+//	type Config struct {
+//		Timeout envstruct.Optional[time.Duration] `env:"timeout"`
+//	}
+type Optional[T any] struct {
+	Value   T
+	Raw     string
+	Present bool
+}
+
+// Get returns the value and whether the env was set
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+// Or returns the value if the env was set, otherwise the fallback
+func (o Optional[T]) Or(fallback T) T {
+	if !o.Present {
+		return fallback
+	}
+
+	return o.Value
+}
+
+// MarshalJSON marshals the value if the env was set, otherwise null
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.Value)
+}
+
+// parseOptional parses the raw value into the Value
+func (o *Optional[T]) parseOptional(p Parser, raw string) error {
+	var value T
+	err := p.ParseInto(&value, raw)
+	if err != nil {
+		return err
+	}
+
+	o.Value = value
+	o.Raw = raw
+	o.Present = true
+
+	return nil
+}
+
+// optionalValue returns the value and whether the env was set
+func (o Optional[T]) optionalValue() (interface{}, bool) {
+	return o.Value, o.Present
+}
+
+// optionalParser is implemented by a pointer to an Optional
+type optionalParser interface {
+	parseOptional(p Parser, raw string) error
+}
+
+// optionalGetter is implemented by an Optional
+type optionalGetter interface {
+	optionalValue() (interface{}, bool)
+}
+
+var optionalParserType = reflect.TypeOf((*optionalParser)(nil)).Elem()
+
+// isOptional returns true if the type is an Optional
+func isOptional(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(optionalParserType)
+}
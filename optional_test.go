@@ -0,0 +1,78 @@
+package envstruct_test
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestOptional() {
+	type Config struct {
+		Timeout envstruct.Optional[time.Duration] `tag:"timeout"`
+		Retries envstruct.Optional[int]           `tag:"retries"`
+		Hosts   envstruct.Optional[[]string]      `tag:"hosts"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:     "app",
+		TagName:    "tag",
+		RequireAll: true,
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("records whether each env was set", func() {
+		os.Setenv("APP_TIMEOUT", "5s")
+		os.Setenv("APP_HOSTS", "a, b")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		timeout, present := config.Timeout.Get()
+		s.True(present)
+		s.Equal(5*time.Second, timeout)
+		s.Equal("5s", config.Timeout.Raw)
+
+		s.False(config.Retries.Present)
+		s.Equal(3, config.Retries.Or(3))
+
+		s.Equal([]string{"a", "b"}, config.Hosts.Value)
+	})
+
+	s.Run("is shown as its value", func() {
+		os.Clearenv()
+		os.Setenv("APP_RETRIES", "2")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		dumped, err := env.Dump(&config)
+		s.NoError(err)
+		s.Equal(map[string]interface{}{"APP_TIMEOUT": nil, "APP_RETRIES": 2, "APP_HOSTS": nil}, dumped)
+
+		encoded, err := json.Marshal(config)
+		s.NoError(err)
+		s.JSONEq(`{"Timeout": null, "Retries": 2, "Hosts": null}`, string(encoded))
+
+		help, err := env.Help(&Config{})
+		s.NoError(err)
+		s.Contains(help, "APP_TIMEOUT  duration")
+	})
+
+	s.Run("fails if the value can not be parsed", func() {
+		os.Clearenv()
+		os.Setenv("APP_RETRIES", "many")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.Error(err)
+		s.False(config.Retries.Present)
+	})
+}
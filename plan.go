@@ -0,0 +1,283 @@
+package envstruct
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// plan holds the values that have been fetched from the env but not yet set on
+// the struct. The values are fetched for every field first, then parsed and
+// only then applied onto the struct, so that the struct is not left half
+// populated if any of the fields fail.
+type plan struct {
+	assignments []*assignment
+
+	// overridden is the path of every field that was set through the Overrides
+	overridden []string
+}
+
+// assignment is a value waiting to be set on a field
+type assignment struct {
+	// field is the field on the struct that the value will be set on
+	field reflect.Value
+
+	// path is the path to the field within the struct, for ex. "Nested.Field"
+	path string
+
+	// name is where the value was fetched from, for ex. the name of the env. It
+	// is used to give context to errors.
+	name string
+
+	// envName is the env name of the field that the value was fetched for. It
+	// differs from the name when the value was looked up under another name
+	// within its source, and is empty if the field has no env names.
+	envName string
+
+	// source is the name of the source that the value was fetched from
+	source string
+
+	// options are the tag options of the field, which can change how the value
+	// is parsed
+	options tagOptions
+
+	// raw is the value as it was fetched, before it is parsed
+	raw string
+
+	// enum are the only raw values that the field can be set to, if any
+	enum []string
+
+	// value is the raw value parsed into the type of the field
+	value reflect.Value
+
+	// entries are the elements of a grown map of structs, which are set on the
+	// map after every other assignment has been applied
+	entries []entry
+}
+
+// stage will add the raw value onto the plan to be parsed and set on the field
+// once the plan is applied.
+func (p *plan) stage(f *field, name string, source string, raw string) {
+	var envName string
+	if containsString(f.envNames, name) || containsString(f.deprecatedNames, name) {
+		envName = name
+	} else if len(f.envNames) > 0 {
+		envName = f.envNames[0]
+	}
+
+	p.assignments = append(p.assignments, &assignment{
+		field:   f.value,
+		path:    f.path,
+		name:    name,
+		envName: envName,
+		source:  source,
+		options: f.options,
+		raw:     raw,
+		enum:    f.enum,
+	})
+}
+
+// grow will add the grown slice or map of structs onto the plan, to replace
+// the one on the struct once the plan is applied. It has no source as it is not
+// fetched itself.
+func (p *plan) grow(f *field) {
+	p.assignments = append(p.assignments, &assignment{
+		field:   f.value,
+		path:    f.path,
+		value:   f.elements,
+		entries: f.entries,
+	})
+}
+
+// parse will parse the raw value of each assignment into the type of its
+// field. In best effort mode, any assignment that fails to parse is dropped
+// from the plan so that the rest can still be applied.
+func (p *plan) parse(e Envstruct) error {
+	var errs Errors
+
+	parsed := make([]*assignment, 0, len(p.assignments))
+	for _, a := range p.assignments {
+		// Grown slices and maps have nothing to parse
+		if a.value.IsValid() {
+			parsed = append(parsed, a)
+			continue
+		}
+
+		value, err := a.parse(e)
+		if err != nil {
+			err = a.fieldError(err)
+
+			if !e.BestEffort {
+				return err
+			}
+
+			errs = errs.collect(err)
+			continue
+		}
+
+		a.value = value
+		parsed = append(parsed, a)
+	}
+
+	p.assignments = parsed
+
+	return errs.errorOrNil()
+}
+
+// parse will parse the raw value of the assignment, decoding it first if the
+// field has any of the options that change how the value is written, such as
+// file, base64 or hex. Decoded values are set as they are on string, []byte
+// and byte array fields, rather than being parsed, so that for ex.
+// certificates keep their line breaks and keys can be fixed size arrays.
+// Fields with the json option are unmarshaled from JSON as a whole instead.
+func (a *assignment) parse(e Envstruct) (reflect.Value, error) {
+	err := a.checkEnum()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	decoded, ok, err := a.decode()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	fieldType := a.field.Type()
+
+	// Values parsed as JSON are unmarshaled as a whole into the field
+	if a.options.json {
+		if !ok {
+			decoded = []byte(a.raw)
+		}
+
+		value := reflect.New(fieldType)
+		err := json.Unmarshal(decoded, value.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return value.Elem(), nil
+	}
+
+	if !ok {
+		return e.parse(fieldType, a.raw, a.options)
+	}
+
+	if fieldType.Kind() == reflect.String || (fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Uint8) {
+		return reflect.ValueOf(decoded).Convert(fieldType), nil
+	}
+
+	if fieldType.Kind() == reflect.Array && fieldType.Elem().Kind() == reflect.Uint8 {
+		if len(decoded) != fieldType.Len() {
+			return reflect.Value{}, fmt.Errorf("decoded value is %d bytes, needs to be %d bytes", len(decoded), fieldType.Len())
+		}
+
+		value := reflect.New(fieldType).Elem()
+		reflect.Copy(value, reflect.ValueOf(decoded))
+
+		return value, nil
+	}
+
+	return e.parse(fieldType, string(decoded), a.options)
+}
+
+// decode returns the raw value after the file has been read, for the file
+// option, and it has been decoded, for the base64 and hex options. False is
+// returned if the field has none of these options.
+func (a *assignment) decode() ([]byte, bool, error) {
+	if !a.options.file && !a.options.base64 && !a.options.hex {
+		return nil, false, nil
+	}
+
+	decoded := []byte(a.raw)
+	if a.options.file {
+		contents, err := os.ReadFile(a.raw)
+		if err != nil {
+			return nil, false, err
+		}
+
+		decoded = contents
+	}
+
+	if a.options.base64 {
+		var err error
+		decoded, err = decodeBase64(string(decoded))
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if a.options.hex {
+		var err error
+		decoded, err = decodeHex(string(decoded))
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return decoded, true, nil
+}
+
+// decodeBase64 decodes the value from standard or URL safe base64, with or
+// without padding. Whitespace is removed first, so that line wrapped values
+// can be decoded.
+func decodeBase64(value string) ([]byte, error) {
+	value = strings.Join(strings.Fields(value), "")
+
+	encoding := base64.StdEncoding
+	if strings.ContainsAny(value, "-_") {
+		encoding = base64.URLEncoding
+	}
+
+	if !strings.HasSuffix(value, "=") {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+
+	decoded, err := encoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// decodeHex decodes the value from hex, with an optional 0x prefix.
+// Whitespace is removed first, the same as for base64.
+func decodeHex(value string) ([]byte, error) {
+	value = strings.Join(strings.Fields(value), "")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// fieldError wraps the error that the assignment failed to parse with
+func (a *assignment) fieldError(err error) *FieldError {
+	fieldErr := &FieldError{Path: a.path, Source: a.source, Err: err}
+	if a.source != SourceOverride && a.source != SourceDefault {
+		fieldErr.Env = a.name
+	}
+
+	return fieldErr
+}
+
+// apply sets every parsed value onto its field. The elements of grown maps of
+// structs are copied into their maps last, once the fields within them are set.
+func (p *plan) apply() {
+	for _, a := range p.assignments {
+		a.field.Set(a.value)
+	}
+
+	for _, a := range p.assignments {
+		for _, entry := range a.entries {
+			a.value.SetMapIndex(entry.key, entry.value)
+		}
+	}
+}
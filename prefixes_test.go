@@ -0,0 +1,68 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestPrefixes() {
+	type Config struct {
+		Host string `tag:"host"`
+		Port int    `tag:"port"`
+		Name string `tag:"name"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:   "newapp",
+		Prefixes: []string{"oldapp"},
+		TagName:  "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("uses the first prefix that has the env set", func() {
+		os.Clearenv()
+		os.Setenv("NEWAPP_HOST", "new")
+		os.Setenv("OLDAPP_HOST", "old")
+		os.Setenv("OLDAPP_PORT", "8080")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Host: "new", Port: 8080}, config)
+	})
+
+	s.Run("tries the envs of the profile first", func() {
+		os.Clearenv()
+		os.Setenv("APP_PROFILE", "dev")
+		os.Setenv("NEWAPP_HOST", "new")
+		os.Setenv("OLDAPP_DEV_HOST", "old-dev")
+
+		env := env
+		env.ProfileEnv = "APP_PROFILE"
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Host: "old-dev"}, config)
+	})
+
+	s.Run("lists the env names of every prefix", func() {
+		env := env
+		env.Prefix = ""
+
+		names, err := env.EnvNames(&Config{})
+		s.NoError(err)
+
+		s.Equal([]string{"OLDAPP_HOST", "OLDAPP_PORT", "OLDAPP_NAME"}, names)
+	})
+}
@@ -0,0 +1,57 @@
+//go:build linux
+
+package envstruct
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SourceProcess is the name of the ProcessEnviron Source
+const SourceProcess = "process"
+
+// ProcessEnviron is a Source for the environment of another process, read
+// from /proc/<pid>/environ. It is meant for diagnostic tools that want to
+// reconstruct the configuration of a running service with the same structs
+// that the service uses. The environment is read once, when the source is
+// created, and is the environment that the process was started with, as
+// changes that a process makes to its own environment are not reflected in
+// /proc.
+type ProcessEnviron struct {
+	// PID is the id of the process that the environment was read from
+	PID int
+
+	values map[string]string
+}
+
+// ReadProcessEnviron reads the environment of the process with the pid. Reading
+// the environment of a process owned by another user requires privileges.
+func ReadProcessEnviron(pid int) (*ProcessEnviron, error) {
+	contents, err := os.ReadFile(fmt.Sprintf("/proc/%d/environ", pid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment of process %d: %w", pid, err)
+	}
+
+	values := map[string]string{}
+	for _, entry := range bytes.Split(contents, []byte{0}) {
+		keyVal := strings.SplitN(string(entry), "=", 2)
+		if len(keyVal) != 2 {
+			continue
+		}
+
+		values[keyVal[0]] = keyVal[1]
+	}
+
+	return &ProcessEnviron{PID: pid, values: values}, nil
+}
+
+// Name returns SourceProcess
+func (p *ProcessEnviron) Name() string { return SourceProcess }
+
+// Lookup returns the value of the env within the environment of the process
+func (p *ProcessEnviron) Lookup(envName string) (string, bool) {
+	value, found := p.values[envName]
+	return value, found
+}
@@ -0,0 +1,76 @@
+//go:build linux
+
+package envstruct_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestProcessEnviron() {
+	type Config struct {
+		Field1 string   `tag:"field1"`
+		Field2 []string `tag:"field2"`
+	}
+
+	s.Run("reads the environment of another process", func() {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+		cmd.Env = []string{"ENVSTRUCT_HELPER_PROCESS=1", "PREFIX_FIELD1=value", "PREFIX_FIELD2=a,b", "EMPTY="}
+		s.NoError(cmd.Start())
+		defer func() {
+			cmd.Process.Kill()
+			cmd.Wait()
+		}()
+
+		// The environment only shows up once the process has been exec'd
+		var process *envstruct.ProcessEnviron
+		s.Eventually(func() bool {
+			var err error
+			process, err = envstruct.ReadProcessEnviron(cmd.Process.Pid)
+			if err != nil {
+				return false
+			}
+
+			_, found := process.Lookup("ENVSTRUCT_HELPER_PROCESS")
+			return found
+		}, 5*time.Second, 10*time.Millisecond)
+
+		env := envstruct.Envstruct{
+			Prefix:  "prefix",
+			TagName: "tag",
+			Sources: []envstruct.Source{process},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Field1: "value", Field2: []string{"a", "b"}}, config)
+
+		value, found := process.Lookup("EMPTY")
+		s.True(found)
+		s.Empty(value)
+	})
+
+	s.Run("fails for a process that does not exist", func() {
+		_, err := envstruct.ReadProcessEnviron(-1)
+		s.EqualError(err, "failed to read environment of process -1: open /proc/-1/environ: no such file or directory")
+	})
+}
+
+// TestHelperProcess is not a real test, it is started as another process by
+// TestProcessEnviron so that its environment can be read
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("ENVSTRUCT_HELPER_PROCESS") != "1" {
+		t.Skip("only run as a helper process")
+	}
+
+	time.Sleep(10 * time.Second)
+}
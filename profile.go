@@ -0,0 +1,89 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// activeProfile returns the profile selected through the ProfileEnv,
+// normalized the same way as the rest of the env name, or an empty string if
+// no profile is selected
+func (e Envstruct) activeProfile() (string, error) {
+	if e.ProfileEnv == "" {
+		return "", nil
+	}
+
+	profile, _, err := e.lookup(e.ProfileEnv, nil, nil)
+	if err != nil || profile == "" {
+		return "", err
+	}
+
+	if len(e.Profiles) > 0 && !containsFold(e.Profiles, profile) {
+		return "", fmt.Errorf("profile %s set in %s is not one of the profiles %s", profile, e.ProfileEnv, strings.Join(e.Profiles, ", "))
+	}
+
+	return e.normalizeName(strings.ToUpper(strings.TrimSpace(profile))), nil
+}
+
+// containsFold returns true if the values contain the value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, strings.TrimSpace(value)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// defaultProfilesTagName is the ProfilesTagName when it is not set
+const defaultProfilesTagName = "profiles"
+
+// fieldProfiles returns the profiles listed within the ProfilesTagName of the
+// field, and whether the "optional" keyword was listed along with them
+func (e Envstruct) fieldProfiles(tag reflect.StructTag) ([]string, bool) {
+	tagName := e.ProfilesTagName
+	if tagName == "" {
+		tagName = defaultProfilesTagName
+	}
+
+	value, found := tag.Lookup(tagName)
+	if !found {
+		return nil, false
+	}
+
+	var profiles []string
+	var requiredInProfiles bool
+	for _, profile := range strings.Split(value, ",") {
+		profile = strings.TrimSpace(profile)
+		if profile == "optional" {
+			requiredInProfiles = true
+		} else if profile != "" {
+			profiles = append(profiles, e.normalizeName(strings.ToUpper(profile)))
+		}
+	}
+
+	return profiles, requiredInProfiles
+}
+
+// filterProfiles drops the fields that are not resolved in the active
+// profile. Fields that are resolved in every profile but only required in
+// their own are kept, although they are optional outside of them.
+func filterProfiles(fields []*field, profile string) []*field {
+	filtered := fields[:0]
+	for _, f := range fields {
+		if len(f.profiles) > 0 && !containsString(f.profiles, profile) {
+			if !f.requiredInProfiles {
+				continue
+			}
+
+			f.options.optional = true
+			f.options.required = false
+		}
+
+		filtered = append(filtered, f)
+	}
+
+	return filtered
+}
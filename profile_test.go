@@ -0,0 +1,162 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestProfiles() {
+	type Config struct {
+		Database struct {
+			Host string `tag:"host"`
+			Port int    `tag:"port"`
+		} `tag:"db"`
+		Debug bool `tag:"debug"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:     "app",
+		TagName:    "tag",
+		RequireAll: true,
+		ProfileEnv: "APP_PROFILE",
+		Profiles:   []string{"dev", "prod"},
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	setEnvs := func(profile string) {
+		os.Clearenv()
+		os.Setenv("APP_PROFILE", profile)
+		os.Setenv("APP_DB_HOST", "localhost")
+		os.Setenv("APP_DB_PORT", "5432")
+		os.Setenv("APP_DEBUG", "false")
+		os.Setenv("APP_DEV_DEBUG", "true")
+		os.Setenv("APP_PROD_DB_HOST", "db.internal")
+	}
+
+	s.Run("looks up the envs of the active profile first", func() {
+		setEnvs("prod")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal("db.internal", config.Database.Host)
+		s.Equal(5432, config.Database.Port)
+		s.False(config.Debug)
+
+		setEnvs("dev")
+
+		config = Config{}
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal("localhost", config.Database.Host)
+		s.True(config.Debug)
+	})
+
+	s.Run("only looks up the unscoped envs without a profile", func() {
+		setEnvs("")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal("localhost", config.Database.Host)
+		s.False(config.Debug)
+	})
+
+	s.Run("lists both names when a required env is missing", func() {
+		setEnvs("dev")
+		os.Unsetenv("APP_DB_PORT")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "required env APP_DEV_DB_PORT or APP_DB_PORT is not set")
+	})
+
+	s.Run("fails on an unknown profile", func() {
+		setEnvs("staging")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "profile staging set in APP_PROFILE is not one of the profiles dev, prod")
+	})
+}
+
+func (s *EnvstructSuite) TestFieldProfiles() {
+	type Config struct {
+		Host     string `tag:"host"`
+		Replicas int    `tag:"replicas" profiles:"prod, staging"`
+		Password string `tag:"password" profiles:"prod,optional"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:     "app",
+		TagName:    "tag",
+		RequireAll: true,
+		ProfileEnv: "APP_PROFILE",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("resolves and requires the fields of the active profile", func() {
+		os.Clearenv()
+		os.Setenv("APP_PROFILE", "prod")
+		os.Setenv("APP_HOST", "localhost")
+		os.Setenv("APP_REPLICAS", "3")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "required env APP_PROD_PASSWORD or APP_PASSWORD is not set")
+
+		os.Setenv("APP_PASSWORD", "secret")
+
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal(3, config.Replicas)
+		s.Equal("secret", config.Password)
+	})
+
+	s.Run("skips the fields of other profiles", func() {
+		os.Clearenv()
+		os.Setenv("APP_PROFILE", "dev")
+		os.Setenv("APP_HOST", "localhost")
+		os.Setenv("APP_REPLICAS", "3")
+		os.Setenv("APP_PASSWORD", "secret")
+
+		config := Config{Replicas: 1}
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal(1, config.Replicas)
+		s.Equal("secret", config.Password)
+
+		os.Unsetenv("APP_PASSWORD")
+
+		config = Config{}
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+		s.Empty(config.Password)
+	})
+
+	s.Run("uses the ProfilesTagName", func() {
+		type Config struct {
+			Replicas int `tag:"replicas" only:"staging"`
+		}
+
+		os.Clearenv()
+		os.Setenv("APP_PROFILE", "staging")
+		os.Setenv("APP_REPLICAS", "2")
+
+		env := env
+		env.ProfilesTagName = "only"
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal(2, config.Replicas)
+	})
+}
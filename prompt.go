@@ -0,0 +1,9 @@
+package envstruct
+
+// Prompter asks for the value of a required env that is not set. The
+// description is from the DescriptionTagName of the field, and secret is true
+// if the field has the "secret" tag option, in which case the input should be
+// hidden. An empty value is treated the same as the env not being set.
+type Prompter interface {
+	Prompt(envName string, description string, secret bool) (string, error)
+}
@@ -0,0 +1,19 @@
+module github.com/clarafu/envstruct/prompt
+
+go 1.21
+
+replace github.com/clarafu/envstruct => ../
+
+require (
+	github.com/clarafu/envstruct v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.6.1
+	golang.org/x/term v0.20.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+	gopkg.in/yaml.v2 v2.3.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
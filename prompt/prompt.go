@@ -0,0 +1,73 @@
+// Package prompt provides an envstruct.Prompter that asks the user for the
+// values of required envs on a terminal, hiding the input of secrets. It is
+// meant for the first run experience of CLI tools.
+//
+// It is a separate module so that applications that do not prompt do not pull
+// in golang.org/x/term.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/clarafu/envstruct"
+	"golang.org/x/term"
+)
+
+// Prompter asks for each value on a line of the input
+type Prompter struct {
+	in     io.Reader
+	reader *bufio.Reader
+	out    io.Writer
+}
+
+// New returns a Prompter that reads the values from the input and writes the
+// prompts to the output. If the input is a terminal, the input of secrets is
+// hidden.
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{
+		in:     in,
+		reader: bufio.NewReader(in),
+		out:    out,
+	}
+}
+
+// Terminal returns a Prompter for stdin and stderr if stdin is a terminal, or
+// nil if it is not so that envstruct fails on missing envs as usual, for ex.
+// when running in CI.
+func Terminal() envstruct.Prompter {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil
+	}
+
+	return New(os.Stdin, os.Stderr)
+}
+
+// Prompt asks for the value of the env
+func (p *Prompter) Prompt(envName string, description string, secret bool) (string, error) {
+	if description != "" {
+		fmt.Fprintf(p.out, "%s (%s): ", envName, description)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", envName)
+	}
+
+	if file, ok := p.in.(*os.File); ok && secret && term.IsTerminal(int(file.Fd())) {
+		value, err := term.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(p.out)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(string(value)), nil
+	}
+
+	line, err := p.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,47 @@
+package prompt_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/clarafu/envstruct"
+	"github.com/clarafu/envstruct/prompt"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/term"
+)
+
+func TestPrompter(t *testing.T) {
+	type Config struct {
+		Host     string `env:"host" desc:"the host to connect to"`
+		Password string `env:"password,secret"`
+	}
+
+	os.Clearenv()
+
+	var out bytes.Buffer
+	env := envstruct.Envstruct{
+		Prefix:     "app",
+		TagName:    "env",
+		RequireAll: true,
+		Prompter:   prompt.New(strings.NewReader("localhost\nhunter2"), &out),
+
+		Parser: envstruct.Parser{Unmarshaler: envstruct.Unmarshal},
+	}
+
+	var config Config
+	err := env.FetchEnv(&config)
+	require.NoError(t, err)
+
+	require.Equal(t, Config{Host: "localhost", Password: "hunter2"}, config)
+	require.Equal(t, "APP_HOST (the host to connect to): APP_PASSWORD: ", out.String())
+}
+
+func TestTerminal(t *testing.T) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		t.Skip("stdin is a terminal")
+	}
+
+	require.Nil(t, prompt.Terminal())
+}
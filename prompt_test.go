@@ -0,0 +1,89 @@
+package envstruct_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type fakePrompter struct {
+	answers map[string]string
+	prompts []string
+	err     error
+}
+
+func (f *fakePrompter) Prompt(envName string, description string, secret bool) (string, error) {
+	f.prompts = append(f.prompts, fmt.Sprintf("%s %q %v", envName, description, secret))
+	return f.answers[envName], f.err
+}
+
+func (s *EnvstructSuite) TestPrompter() {
+	type Config struct {
+		Host     string `tag:"host" desc:"the host to connect to"`
+		Port     int    `tag:"port,optional"`
+		Password string `tag:"password,secret"`
+	}
+
+	defer os.Clearenv()
+
+	s.Run("prompts for required envs that are not set", func() {
+		prompter := &fakePrompter{answers: map[string]string{"APP_HOST": "localhost", "APP_PASSWORD": "hunter2"}}
+		observer := &recordingObserver{}
+		env := envstruct.Envstruct{
+			Prefix:     "app",
+			TagName:    "tag",
+			RequireAll: true,
+			Prompter:   prompter,
+			Observer:   observer,
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		os.Setenv("APP_PASSWORD", "from env")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Host: "localhost", Password: "from env"}, config)
+		s.Equal([]string{`APP_HOST "the host to connect to" false`}, prompter.prompts)
+		s.Equal(map[string]int{envstruct.SourceEnv: 1, envstruct.SourcePrompt: 1}, observer.events[0].Sources)
+	})
+
+	s.Run("hides secrets and fails on empty answers", func() {
+		os.Clearenv()
+
+		prompter := &fakePrompter{answers: map[string]string{"APP_HOST": "localhost"}}
+		env := envstruct.Envstruct{
+			Prefix:     "app",
+			TagName:    "tag",
+			RequireAll: true,
+			Prompter:   prompter,
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "required env APP_PASSWORD is not set")
+		s.Equal([]string{`APP_HOST "the host to connect to" false`, `APP_PASSWORD "" true`}, prompter.prompts)
+	})
+
+	s.Run("fails if prompting fails", func() {
+		env := envstruct.Envstruct{
+			Prefix:     "app",
+			TagName:    "tag",
+			RequireAll: true,
+			Prompter:   &fakePrompter{err: errors.New("not a terminal")},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "failed to prompt for APP_HOST: not a terminal")
+	})
+}
@@ -0,0 +1,165 @@
+package envstruct
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SourcePropertiesFile is the name of the PropertiesFile Source
+const SourcePropertiesFile = "properties_file"
+
+// PropertiesFile is a Source for a Java .properties file, to ease migrating
+// services to Go with their existing config files. The dots within each key
+// are mapped onto the nesting of the env names, so that "db.host" is exposed
+// as DB_HOST, along with any other character that is not a letter or digit.
+//
+// Keys and values can be separated by "=", ":" or whitespace, lines starting
+// with "#" or "!" are comments, and lines ending with a backslash are
+// continued onto the next line. The escapes \t, \n, \r, \f and \uXXXX are
+// supported within values.
+type PropertiesFile struct {
+	// Path is the path of the file that was read
+	Path string
+
+	*fileValues
+}
+
+// ReadPropertiesFile reads the .properties file at the path
+func ReadPropertiesFile(path string) (*PropertiesFile, error) {
+	properties := &PropertiesFile{Path: path, fileValues: newFileValues(path, readPropertiesFile)}
+
+	err := properties.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
+
+func readPropertiesFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read properties file: %w", err)
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(file)
+
+	var logical string
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if logical == "" && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		// A line ending with an odd number of backslashes is continued onto the
+		// next line
+		if trailing := len(line) - len(strings.TrimRight(line, `\`)); trailing%2 == 1 {
+			logical += line[:len(line)-1]
+			continue
+		}
+
+		logical += line
+
+		key, value := splitProperty(logical)
+		logical = ""
+
+		key, err := unescapeProperty(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse properties file %s on line %d: %w", path, lineNumber, err)
+		}
+
+		value, err = unescapeProperty(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse properties file %s on line %d: %w", path, lineNumber, err)
+		}
+
+		values[flatName(key)] = value
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read properties file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// splitProperty splits the line at the first unescaped separator, which is
+// either "=", ":" or whitespace
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			// Skip the escaped character
+			i++
+
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+
+		case ' ', '\t', '\f':
+			value := strings.TrimLeft(line[i:], " \t\f")
+			if value != "" && (value[0] == '=' || value[0] == ':') {
+				value = strings.TrimLeft(value[1:], " \t\f")
+			}
+
+			return line[:i], value
+		}
+	}
+
+	return line, ""
+}
+
+// unescapeProperty replaces the escapes within the key or value
+func unescapeProperty(escaped string) (string, error) {
+	if !strings.Contains(escaped, `\`) {
+		return escaped, nil
+	}
+
+	var unescaped strings.Builder
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '\\' || i == len(escaped)-1 {
+			unescaped.WriteByte(escaped[i])
+			continue
+		}
+
+		i++
+		switch escaped[i] {
+		case 't':
+			unescaped.WriteByte('\t')
+		case 'n':
+			unescaped.WriteByte('\n')
+		case 'r':
+			unescaped.WriteByte('\r')
+		case 'f':
+			unescaped.WriteByte('\f')
+		case 'u':
+			if i+5 > len(escaped) {
+				return "", fmt.Errorf("malformed \\u escape in %q", escaped)
+			}
+
+			var r rune
+			_, err := fmt.Sscanf(escaped[i+1:i+5], "%04x", &r)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\u escape in %q", escaped)
+			}
+
+			unescaped.WriteRune(r)
+			i += 4
+		default:
+			unescaped.WriteByte(escaped[i])
+		}
+	}
+
+	return unescaped.String(), nil
+}
+
+// Name returns SourcePropertiesFile
+func (p *PropertiesFile) Name() string { return SourcePropertiesFile }
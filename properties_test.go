@@ -0,0 +1,69 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestPropertiesFile() {
+	type Config struct {
+		Database struct {
+			Host     string   `tag:"host"`
+			Port     int      `tag:"port"`
+			Replicas []string `tag:"replicas"`
+		} `tag:"db"`
+		Server struct {
+			Name    string `tag:"name"`
+			Message string `tag:"message"`
+		} `tag:"server"`
+		LogLevel string `tag:"log_level"`
+	}
+
+	defer os.Clearenv()
+
+	s.Run("maps dotted keys onto the nested names", func() {
+		path := filepath.Join(s.T().TempDir(), "application.properties")
+		s.NoError(os.WriteFile(path, []byte(`# Database settings
+db.host=localhost
+db.port : 5432
+db.replicas = a,\
+              b
+! Server settings
+server.name    caf\u00e9
+server.message=hello\tworld
+log-level=debug
+`), 0600))
+
+		file, err := envstruct.ReadPropertiesFile(path)
+		s.NoError(err)
+
+		env := envstruct.Envstruct{
+			TagName: "tag",
+			Sources: []envstruct.Source{file},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("localhost", config.Database.Host)
+		s.Equal(5432, config.Database.Port)
+		s.Equal([]string{"a", "b"}, config.Database.Replicas)
+		s.Equal("café", config.Server.Name)
+		s.Equal("hello\tworld", config.Server.Message)
+		s.Equal("debug", config.LogLevel)
+	})
+
+	s.Run("fails on malformed escapes", func() {
+		path := filepath.Join(s.T().TempDir(), "invalid.properties")
+		s.NoError(os.WriteFile(path, []byte("name=\\u00\n"), 0600))
+
+		_, err := envstruct.ReadPropertiesFile(path)
+		s.EqualError(err, "failed to parse properties file "+path+` on line 1: malformed \u escape in "\\u00"`)
+	})
+}
@@ -0,0 +1,72 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestQuotedItems() {
+	type Config struct {
+		Names  []string          `tag:"names"`
+		Ports  []int             `tag:"ports"`
+		Labels map[string]string `tag:"labels"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("allows the delimiter within quoted items", func() {
+		os.Clearenv()
+		os.Setenv("APP_NAMES", `"Doe, Jane",Smith, "say ""hi"""`)
+		os.Setenv("APP_PORTS", `"80", 443`)
+		os.Setenv("APP_LABELS", `"team:a, b",env:prod`)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Names:  []string{"Doe, Jane", "Smith", `say "hi"`},
+			Ports:  []int{80, 443},
+			Labels: map[string]string{"team": "a, b", "env": "prod"},
+		}, config)
+	})
+
+	s.Run("fails on a missing closing quote", func() {
+		os.Clearenv()
+		os.Setenv("APP_NAMES", `"Doe, Jane`)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, `field Names (env APP_NAMES): quoted item "Doe, Jane is missing its closing quote`)
+	})
+
+	s.Run("fails on text after the closing quote", func() {
+		os.Clearenv()
+		os.Setenv("APP_NAMES", `"Doe"Jane,Smith`)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, `field Names (env APP_NAMES): unexpected "Jane,Smith" after quoted item "Doe"`)
+	})
+
+	s.Run("quotes items that contain the delimiter when exporting", func() {
+		os.Clearenv()
+
+		config := Config{Names: []string{"Doe, Jane", "Smith"}}
+		script, err := env.ExportScript(&config, false)
+		s.NoError(err)
+		s.Contains(script, `export APP_NAMES='"Doe, Jane",Smith'`)
+	})
+}
@@ -0,0 +1,170 @@
+package envstruct
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// SourceRedis is the name of the Redis Source
+const SourceRedis = "redis"
+
+// RedisDebounce is how long to wait after a keyspace notification before
+// reloading, so that a burst of changes, for ex. an MSET, only causes a single
+// reload
+const RedisDebounce = 100 * time.Millisecond
+
+// RedisClient is the part of a Redis client that the Redis Source needs, so
+// that any client library can be used without envstruct depending on it. The
+// found return value is false if the key or field does not exist.
+type RedisClient interface {
+	Get(key string) (value string, found bool, err error)
+	HGet(key string, field string) (value string, found bool, err error)
+}
+
+// RedisHashClient can be implemented by the RedisClient to allow the Redis
+// Source to read every field of its Hash with a single request at the start
+// of each fetch, rather than one request for each candidate name of each
+// field.
+type RedisHashClient interface {
+	HGetAll(key string) (map[string]string, error)
+}
+
+// RedisSubscriber can be implemented by the RedisClient to allow the Redis
+// Source to watch for changes through keyspace notifications. PSubscribe
+// subscribes to the channels matching the pattern and returns the channel
+// that the name of each notified channel is sent on, along with a func that
+// ends the subscription.
+type RedisSubscriber interface {
+	PSubscribe(pattern string) (messages <-chan string, unsubscribe func() error, err error)
+}
+
+// Redis is a Source for envs stored within Redis, for teams that already keep
+// dynamic flags there. Each env is looked up as the key named after the env
+// under the Prefix, or as a field of the Hash if it is set.
+type Redis struct {
+	// Client is the client used to talk to Redis
+	Client RedisClient
+
+	// Prefix is prepended to the name of each env to build its key, for ex.
+	// "flags:" looks up APP_DEBUG as "flags:APP_DEBUG"
+	Prefix string
+
+	// Hash is optional and if set, is the key of a hash that holds the envs as
+	// its fields, in which case the Prefix is not used
+	Hash string
+}
+
+// NewRedis returns a Redis Source that looks up each env as a key under the
+// prefix
+func NewRedis(client RedisClient, prefix string) *Redis {
+	return &Redis{Client: client, Prefix: prefix}
+}
+
+// NewRedisHash returns a Redis Source that looks up each env as a field of the
+// hash
+func NewRedisHash(client RedisClient, hash string) *Redis {
+	return &Redis{Client: client, Hash: hash}
+}
+
+// Name returns SourceRedis
+func (r *Redis) Name() string { return SourceRedis }
+
+// Lookup returns the value of the env in Redis, see LookupErr
+func (r *Redis) Lookup(envName string) (string, bool) {
+	value, found, _ := r.LookupErr(envName)
+	return value, found
+}
+
+// LookupErr returns the value of the env in Redis. An error is returned if
+// Redis could not be reached, so that it is not mistaken for the env not
+// being set.
+func (r *Redis) LookupErr(envName string) (string, bool, error) {
+	if r.Hash != "" {
+		return r.Client.HGet(r.Hash, envName)
+	}
+
+	return r.Client.Get(r.Prefix + envName)
+}
+
+// Index reads every field of the Hash if the Client implements
+// RedisHashClient, see IndexedSource. Otherwise each env is looked up on its
+// own.
+func (r *Redis) Index() (map[string]string, error) {
+	hashClient, ok := r.Client.(RedisHashClient)
+	if !ok || r.Hash == "" {
+		return nil, nil
+	}
+
+	return hashClient.HGetAll(r.Hash)
+}
+
+// Watch subscribes to the keyspace notifications of the keys of the source and
+// reloads the Watcher whenever they change. onReload is called with the
+// changed fields or the error that the reload failed with. Reloads that do not
+// change any fields are not reported. The returned func stops watching.
+//
+// The Client needs to implement RedisSubscriber, and keyspace notifications
+// need to be enabled on the Redis server, for ex. with
+// "CONFIG SET notify-keyspace-events KA".
+func (r *Redis) Watch(w *Watcher, onReload func([]Change, error)) (func() error, error) {
+	subscriber, ok := r.Client.(RedisSubscriber)
+	if !ok {
+		return nil, errors.New("failed to watch redis, the client does not implement RedisSubscriber")
+	}
+
+	pattern := "__keyspace@*__:" + r.Prefix + "*"
+	if r.Hash != "" {
+		pattern = "__keyspace@*__:" + r.Hash
+	}
+
+	messages, unsubscribe, err := subscriber.PSubscribe(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// The timer is only started once there is a change to debounce
+		timer := time.NewTimer(RedisDebounce)
+		timer.Stop()
+
+		for {
+			select {
+			case <-done:
+				timer.Stop()
+				return
+
+			case _, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				timer.Reset(RedisDebounce)
+
+			case <-timer.C:
+				changes, err := w.Reload()
+				if err != nil || len(changes) > 0 {
+					onReload(changes, err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() error {
+		var err error
+		once.Do(func() {
+			close(done)
+			err = unsubscribe()
+			wg.Wait()
+		})
+
+		return err
+	}, nil
+}
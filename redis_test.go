@@ -0,0 +1,169 @@
+package envstruct_test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+// fakeRedis is an in memory RedisClient and RedisSubscriber
+type fakeRedis struct {
+	mu       sync.Mutex
+	keys     map[string]string
+	hashes   map[string]map[string]string
+	patterns map[string]chan string
+	err      error
+
+	hgetalls int
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		keys:     map[string]string{},
+		hashes:   map[string]map[string]string{},
+		patterns: map[string]chan string{},
+	}
+}
+
+func (f *fakeRedis) Get(key string) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, found := f.keys[key]
+	return value, found, f.err
+}
+
+func (f *fakeRedis) HGet(key string, field string) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, found := f.hashes[key][field]
+	return value, found, f.err
+}
+
+func (f *fakeRedis) HGetAll(key string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.hgetalls++
+	return f.hashes[key], f.err
+}
+
+func (f *fakeRedis) PSubscribe(pattern string) (<-chan string, func() error, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	messages := make(chan string, 10)
+	f.patterns[pattern] = messages
+
+	return messages, func() error { return nil }, nil
+}
+
+// set sets the key and sends the keyspace notification the same way Redis
+// would
+func (f *fakeRedis) set(key string, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys[key] = value
+
+	channel := "__keyspace@0__:" + key
+	for pattern, messages := range f.patterns {
+		// Keys do not contain slashes, so path.Match works as a glob
+		if matched, _ := path.Match(pattern, channel); matched {
+			messages <- channel
+		}
+	}
+}
+
+func (s *EnvstructSuite) TestRedis() {
+	type Config struct {
+		Debug bool   `tag:"debug"`
+		Level string `tag:"level"`
+	}
+
+	defer os.Clearenv()
+
+	newEnv := func(source envstruct.Source) envstruct.Envstruct {
+		return envstruct.Envstruct{
+			Prefix:  "app",
+			TagName: "tag",
+			Sources: []envstruct.Source{source},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+	}
+
+	s.Run("looks up keys under the prefix", func() {
+		client := newFakeRedis()
+		client.keys["flags:APP_DEBUG"] = "true"
+
+		var config Config
+		err := newEnv(envstruct.NewRedis(client, "flags:")).FetchEnv(&config)
+		s.NoError(err)
+		s.Equal(Config{Debug: true}, config)
+	})
+
+	s.Run("looks up fields of the hash", func() {
+		client := newFakeRedis()
+		client.hashes["flags"] = map[string]string{"APP_LEVEL": "info"}
+
+		var config Config
+		err := newEnv(envstruct.NewRedisHash(client, "flags")).FetchEnv(&config)
+		s.NoError(err)
+		s.Equal(Config{Level: "info"}, config)
+
+		// The whole hash is read with a single request
+		s.Equal(1, client.hgetalls)
+	})
+
+	s.Run("fails if redis can not be reached", func() {
+		client := newFakeRedis()
+		client.err = errors.New("connection refused")
+
+		var config Config
+		err := newEnv(envstruct.NewRedis(client, "flags:")).FetchEnv(&config)
+		s.EqualError(err, "failed to look up APP_DEBUG in source redis: connection refused")
+
+		err = newEnv(envstruct.NewRedisHash(client, "flags")).FetchEnv(&config)
+		s.EqualError(err, "failed to index source redis: connection refused")
+	})
+
+	s.Run("reloads on keyspace notifications", func() {
+		client := newFakeRedis()
+		client.keys["flags:APP_LEVEL"] = "info"
+
+		source := envstruct.NewRedis(client, "flags:")
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(newEnv(source), &config)
+		s.NoError(err)
+
+		reloads := make(chan []envstruct.Change, 1)
+		stop, err := source.Watch(watcher, func(changes []envstruct.Change, err error) {
+			s.Assert().NoError(err)
+			reloads <- changes
+		})
+		s.NoError(err)
+		defer stop()
+
+		client.set("flags:APP_LEVEL", "debug")
+		client.set("other:APP_LEVEL", "error")
+
+		select {
+		case changes := <-reloads:
+			s.Equal([]envstruct.Change{{Name: "APP_LEVEL", Old: "info", New: "debug"}}, changes)
+		case <-time.After(5 * time.Second):
+			s.Fail("timed out waiting for the reload")
+		}
+
+		watcher.RLock()
+		defer watcher.RUnlock()
+		s.Equal("debug", config.Level)
+	})
+}
@@ -0,0 +1,94 @@
+package envstruct
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+// Report describes where the value of each field of a struct came from, so
+// that the effective configuration can be logged at startup without logging
+// the values themselves.
+type Report struct {
+	// Fields are the fields that can be fetched from the env, in the order
+	// that they appear within the struct
+	Fields []FieldReport `json:"fields"`
+}
+
+// FieldReport describes where the value of a single field came from
+type FieldReport struct {
+	// Field is the path to the field within the struct, for ex. "Nested.Field"
+	Field string `json:"field"`
+
+	// Env is the name of the env that the field was populated from. It is
+	// empty if the field was not populated, or was populated through the
+	// Overrides or its default.
+	Env string `json:"env,omitempty"`
+
+	// Source is the name of the source that the field was populated from (for
+	// ex. SourceEnv), or empty if the field was not populated
+	Source string `json:"source,omitempty"`
+
+	// Default is true if the field was populated with its default, as none of
+	// its envs were set
+	Default bool `json:"default,omitempty"`
+}
+
+// FetchEnvWithReport will fetch the envs into the struct in the same way as
+// FetchEnv, and return a report of which fields were populated and where from.
+// If the fetch fails the report is nil, unless BestEffort is on in which case
+// it describes the fields that were still populated.
+func (e Envstruct) FetchEnvWithReport(object interface{}) (*Report, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to parse env into object, needs to be type struct")
+	}
+
+	start := time.Now()
+
+	p, err := e.fetchEnv(object)
+	e.observe(start, p, err)
+
+	if p == nil {
+		return nil, err
+	}
+
+	report, reportErr := e.report(object, p)
+	if reportErr != nil {
+		return nil, reportErr
+	}
+
+	return report, err
+}
+
+// report builds the report from the plan once it has been applied, so that the
+// fields of any slices and maps of structs that were grown are included
+func (e Envstruct) report(object interface{}, p *plan) (*Report, error) {
+	fields, err := e.fields(object)
+	if err != nil && !e.BestEffort {
+		return nil, err
+	}
+
+	assignments := map[string]*assignment{}
+	for _, a := range p.assignments {
+		if a.source != "" {
+			assignments[a.path] = a
+		}
+	}
+
+	report := &Report{Fields: []FieldReport{}}
+	for _, f := range fields {
+		fieldReport := FieldReport{Field: f.path}
+		if a, found := assignments[f.path]; found {
+			fieldReport.Source = a.source
+			fieldReport.Default = a.source == SourceDefault
+
+			if a.source != SourceOverride && a.source != SourceDefault {
+				fieldReport.Env = a.name
+			}
+		}
+
+		report.Fields = append(report.Fields, fieldReport)
+	}
+
+	return report, nil
+}
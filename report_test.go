@@ -0,0 +1,79 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestFetchEnvWithReport() {
+	type Config struct {
+		Host     string `tag:"host|hostname"`
+		Port     int    `tag:"port" default:"8080"`
+		Debug    bool   `tag:"debug"`
+		Replicas int    `tag:"replicas"`
+		Upstream []struct {
+			URL string `tag:"url"`
+		} `tag:"upstreams"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:         "app",
+		TagName:        "tag",
+		DefaultTagName: "default",
+		Overrides:      map[string]string{"Replicas": "3"},
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("reports where each field came from", func() {
+		os.Clearenv()
+		os.Setenv("APP_HOSTNAME", "localhost")
+		os.Setenv("APP_UPSTREAMS_0_URL", "http://a")
+
+		var config Config
+		report, err := env.FetchEnvWithReport(&config)
+		s.NoError(err)
+
+		s.Equal("localhost", config.Host)
+		s.Equal(&envstruct.Report{Fields: []envstruct.FieldReport{
+			{Field: "Host", Env: "APP_HOSTNAME", Source: envstruct.SourceEnv},
+			{Field: "Port", Source: envstruct.SourceDefault, Default: true},
+			{Field: "Debug"},
+			{Field: "Replicas", Source: envstruct.SourceOverride},
+			{Field: "Upstream.0.URL", Env: "APP_UPSTREAMS_0_URL", Source: envstruct.SourceEnv},
+		}}, report)
+	})
+
+	s.Run("returns no report when the fetch fails", func() {
+		os.Clearenv()
+		os.Setenv("APP_PORT", "abc")
+
+		var config Config
+		report, err := env.FetchEnvWithReport(&config)
+		s.Error(err)
+		s.Nil(report)
+	})
+
+	s.Run("reports the fields that were populated in best effort mode", func() {
+		os.Clearenv()
+		os.Setenv("APP_HOST", "localhost")
+		os.Setenv("APP_PORT", "abc")
+
+		env := env
+		env.BestEffort = true
+
+		var config Config
+		report, err := env.FetchEnvWithReport(&config)
+		s.Error(err)
+
+		s.Equal(envstruct.FieldReport{Field: "Host", Env: "APP_HOST", Source: envstruct.SourceEnv}, report.Fields[0])
+		s.Equal(envstruct.FieldReport{Field: "Port"}, report.Fields[1])
+	})
+}
@@ -0,0 +1,48 @@
+package envstruct
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Reset will zero every field on the struct that is populated from the
+// environment, leaving any other fields as they are. The fields are found by
+// fetching the envs in the same way as FetchEnv does, so any field that
+// FetchEnv would set from the current environment is reset. This is useful in
+// tests and in reload flows that want to start from a clean slate.
+func (e Envstruct) Reset(object interface{}) error {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return errors.New("failed to reset object, needs to be type struct")
+	}
+
+	// Only the fields that the envs would be set on are needed, so turn off
+	// anything that would stop the fetch early or ask the user for values. Any
+	// field that fails to parse could not have been populated from the
+	// environment so it is left alone.
+	e.RequireAll = false
+	e.OnConflict = nil
+	e.OnDeprecated = nil
+	e.Prompter = nil
+	e.BestEffort = true
+
+	// The errors of fields that failed to parse are expected, but if nothing
+	// could be resolved at all, for ex. because a source failed, then there is
+	// nothing to reset
+	p, err := e.resolve(object)
+	if p == nil {
+		return err
+	}
+
+	for _, a := range p.assignments {
+		// Only the values fetched from a source are reset, as the fields pinned
+		// through the Overrides or set to their defaults were not populated from
+		// the environment
+		if a.source == "" || a.source == SourceOverride || a.source == SourceDefault {
+			continue
+		}
+
+		a.field.Set(reflect.Zero(a.field.Type()))
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package envstruct_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+// failingIndexSource is an IndexedSource that fails to be indexed
+type failingIndexSource struct{}
+
+func (failingIndexSource) Name() string { return "failing" }
+
+func (failingIndexSource) Lookup(string) (string, bool) { return "", false }
+
+func (failingIndexSource) Index() (map[string]string, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (s *EnvstructSuite) TestReset() {
+	type Config struct {
+		Field1 string `tag:"field1"`
+		Field2 string `tag:"field2"`
+		Field3 int    `tag:"field3"`
+		Nested struct {
+			Field4 []string `tag:"field4"`
+		} `tag:"nested"`
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:     "prefix",
+		TagName:    "tag",
+		RequireAll: true,
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	os.Setenv("PREFIX_FIELD1", "value")
+	os.Setenv("PREFIX_FIELD3", "notanint")
+	os.Setenv("PREFIX_NESTED_FIELD4", "a,b")
+	defer os.Clearenv()
+
+	config := Config{
+		Field1: "value",
+		Field2: "code",
+		Field3: 3,
+	}
+	config.Nested.Field4 = []string{"a", "b"}
+
+	err := env.Reset(&config)
+	s.NoError(err)
+
+	s.Equal(Config{
+		Field2: "code",
+		Field3: 3,
+	}, config)
+
+	s.Run("returns the error of a source that fails", func() {
+		env := env
+		env.Sources = []envstruct.Source{failingIndexSource{}}
+
+		config := Config{Field1: "value"}
+		err := env.Reset(&config)
+		s.EqualError(err, "failed to index source failing: connection refused")
+		s.Equal("value", config.Field1)
+	})
+
+	s.Run("leaves the fields pinned through the overrides", func() {
+		env := env
+		env.Overrides = map[string]string{"Field2": "pinned"}
+
+		config := Config{Field1: "value", Field2: "pinned"}
+		err := env.Reset(&config)
+		s.NoError(err)
+		s.Equal(Config{Field2: "pinned"}, config)
+	})
+
+	s.Run("does not prompt for required fields", func() {
+		type Config struct {
+			Host string `tag:"host,required"`
+		}
+
+		prompter := &fakePrompter{answers: map[string]string{"PREFIX_HOST": "localhost"}}
+
+		env := env
+		env.Prompter = prompter
+
+		config := Config{Host: "code"}
+		err := env.Reset(&config)
+		s.NoError(err)
+
+		s.Empty(prompter.prompts)
+		s.Equal(Config{Host: "code"}, config)
+	})
+
+	s.Run("leaves the fields that only have a default", func() {
+		type Config struct {
+			Host string `tag:"host" default:"localhost"`
+		}
+
+		env := env
+		env.DefaultTagName = "default"
+
+		config := Config{Host: "code"}
+		err := env.Reset(&config)
+		s.NoError(err)
+		s.Equal(Config{Host: "code"}, config)
+	})
+}
@@ -0,0 +1,87 @@
+package envstruct
+
+import "fmt"
+
+// rotation is a callback subscribed to the rotation of a secret
+type rotation struct {
+	name     string
+	callback func(value interface{})
+}
+
+// OnRotate subscribes the callback to the rotation of the secret fetched from
+// the env with the name, which needs to be a field with the "secret" tag
+// option. Whenever a Reload changes the value of the secret, the callback is
+// called with the new value of the field, so that for ex. database pools and
+// TLS listeners can be rebuilt without a restart. Callbacks are called after
+// the reload has finished and the struct has been unlocked, within the
+// goroutine that called Reload. The returned function unsubscribes the
+// callback.
+func (w *Watcher) OnRotate(name string, callback func(value interface{})) (func(), error) {
+	w.mu.RLock()
+	secrets, err := w.env.secretFields(w.object)
+	w.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, found := secrets[name]; !found {
+		return nil, fmt.Errorf("failed to subscribe to rotation of %s, it is not a secret field", name)
+	}
+
+	r := &rotation{name: name, callback: callback}
+
+	w.rotationsMu.Lock()
+	w.rotations = append(w.rotations, r)
+	w.rotationsMu.Unlock()
+
+	return func() {
+		w.rotationsMu.Lock()
+		defer w.rotationsMu.Unlock()
+
+		for i, subscribed := range w.rotations {
+			if subscribed == r {
+				w.rotations = append(w.rotations[:i:i], w.rotations[i+1:]...)
+				return
+			}
+		}
+	}, nil
+}
+
+// secretFields returns the values of the secret fields of the struct, keyed by
+// the name of their env
+func (e Envstruct) secretFields(object interface{}) (map[string]interface{}, error) {
+	fields, err := e.fields(object)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := map[string]interface{}{}
+	for _, f := range fields {
+		if f.tagged && f.options.secret && len(f.envNames) > 0 {
+			secrets[f.envNames[0]] = f.value.Interface()
+		}
+	}
+
+	return secrets, nil
+}
+
+// notifyRotations calls the callbacks subscribed to each of the secrets that
+// were changed
+func (w *Watcher) notifyRotations(changes []Change, secrets map[string]interface{}) {
+	w.rotationsMu.Lock()
+	rotations := append([]*rotation(nil), w.rotations...)
+	w.rotationsMu.Unlock()
+
+	for _, change := range changes {
+		value, found := secrets[change.Name]
+		if !found {
+			continue
+		}
+
+		for _, r := range rotations {
+			if r.name == change.Name {
+				r.callback(value)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestOnRotate() {
+	type Config struct {
+		Host     string `tag:"host"`
+		Password string `tag:"password,secret"`
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "prefix",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	defer os.Clearenv()
+
+	s.Run("calls the callback with the new value of the secret", func() {
+		os.Setenv("PREFIX_PASSWORD", "hunter2")
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		var rotated []interface{}
+		unsubscribe, err := watcher.OnRotate("PREFIX_PASSWORD", func(value interface{}) {
+			rotated = append(rotated, value)
+		})
+		s.NoError(err)
+
+		os.Setenv("PREFIX_HOST", "example.com")
+
+		_, err = watcher.Reload()
+		s.NoError(err)
+		s.Empty(rotated)
+
+		os.Setenv("PREFIX_PASSWORD", "hunter3")
+
+		_, err = watcher.Reload()
+		s.NoError(err)
+		s.Equal([]interface{}{"hunter3"}, rotated)
+
+		unsubscribe()
+		os.Setenv("PREFIX_PASSWORD", "hunter4")
+
+		_, err = watcher.Reload()
+		s.NoError(err)
+		s.Equal([]interface{}{"hunter3"}, rotated)
+	})
+
+	s.Run("only subscribes to secret fields", func() {
+		os.Clearenv()
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		_, err = watcher.OnRotate("PREFIX_HOST", func(interface{}) {})
+		s.EqualError(err, "failed to subscribe to rotation of PREFIX_HOST, it is not a secret field")
+	})
+}
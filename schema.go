@@ -0,0 +1,105 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Schema is the compiled form of a struct, holding the names of the envs of
+// every field along with their tag options. Fetching through a Schema skips
+// parsing the tags and walking through the struct, which adds up when the same
+// struct is fetched many times, for ex. a config per tenant on every request
+// or within a test suite. A Schema is immutable and safe to use concurrently.
+type Schema struct {
+	env        Envstruct
+	objectType reflect.Type
+	fields     []*field
+
+	// dynamic is true if the struct holds slices or maps of structs or
+	// pointers to structs, whose fields depend on the envs that are set, or if
+	// the ProfileEnv is set, as the profile depends on the source being fetched
+	// from. The struct is then walked through on every fetch.
+	dynamic bool
+}
+
+// Compile walks through the struct once and returns its Schema, failing with
+// the same errors as FetchEnv would for the tags of the struct. The object can
+// be the struct or a pointer to it. Its values are not used.
+func (e Envstruct) Compile(object interface{}) (*Schema, error) {
+	v := reflect.ValueOf(object)
+	if v.Kind() != reflect.Ptr {
+		copied := reflect.New(v.Type())
+		copied.Elem().Set(v)
+		v = copied
+	}
+
+	if v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to compile schema, needs to be a struct or a pointer to a struct")
+	}
+
+	// The profile is selected by the source that each fetch is given, so the
+	// tags are only checked here without a profile
+	unscoped := e
+	unscoped.ProfileEnv = ""
+
+	fields, err := unscoped.fields(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the description of each field is kept, the values are looked up on
+	// the struct being fetched into
+	compiled := make([]*field, len(fields))
+	for i, f := range fields {
+		copied := *f
+		copied.value = reflect.Value{}
+		compiled[i] = &copied
+	}
+
+	return &Schema{
+		env:        e,
+		objectType: v.Type(),
+		fields:     compiled,
+		dynamic:    e.ProfileEnv != "" || hasDynamicFields(v.Type().Elem(), map[reflect.Type]bool{}),
+	}, nil
+}
+
+// Fetch fetches the envs into the struct the same way as FetchEnv, which
+// needs to be a pointer to the type of struct that the Schema was compiled
+// from. If the source is not nil, the envs are only looked up within it rather
+// than the Sources of the Envstruct, for ex. a source holding the config of a
+// single tenant.
+func (s *Schema) Fetch(source Source, object interface{}) error {
+	if reflect.TypeOf(object) != s.objectType {
+		return fmt.Errorf("failed to fetch env into %T, the schema was compiled for %s", object, s.objectType)
+	}
+
+	env := s.env
+	env.schema = s
+	if source != nil {
+		env.Sources = []Source{source}
+	}
+
+	return env.FetchEnv(object)
+}
+
+// fieldsOf returns the compiled fields with their values within the struct.
+// Fields within nested pointers to structs that are nil are skipped.
+func (s *Schema) fieldsOf(object interface{}) []*field {
+	v := reflect.ValueOf(object).Elem()
+
+	fields := make([]*field, 0, len(s.fields))
+	for _, f := range s.fields {
+		value, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		copied := *f
+		copied.value = value
+		fields = append(fields, &copied)
+	}
+
+	return fields
+}
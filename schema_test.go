@@ -0,0 +1,183 @@
+package envstruct_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type tenantSource map[string]string
+
+func (t tenantSource) Name() string { return "tenant" }
+
+func (t tenantSource) Lookup(envName string) (string, bool) {
+	value, found := t[envName]
+	return value, found
+}
+
+func (s *EnvstructSuite) TestSchema() {
+	type Database struct {
+		Host string `tag:"host"`
+		Port int    `tag:"port,optional"`
+	}
+
+	type Config struct {
+		Database Database  `tag:"db"`
+		Replica  *Database `tag:"replica"`
+		Debug    bool      `tag:"debug|verbose"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:     "app",
+		TagName:    "tag",
+		RequireAll: true,
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	schema, err := env.Compile(Config{})
+	s.NoError(err)
+
+	s.Run("fetches the same way as FetchEnv", func() {
+		os.Clearenv()
+		os.Setenv("APP_DB_HOST", "localhost")
+		os.Setenv("APP_VERBOSE", "true")
+
+		var config Config
+		err := schema.Fetch(nil, &config)
+		s.NoError(err)
+
+		var expected Config
+		err = env.FetchEnv(&expected)
+		s.NoError(err)
+
+		s.Equal(Config{Database: Database{Host: "localhost"}, Debug: true}, config)
+		s.Equal(expected, config)
+	})
+
+	s.Run("looks up the envs within the source", func() {
+		var config Config
+		err := schema.Fetch(tenantSource{"APP_DB_HOST": "tenant.internal", "APP_DEBUG": "false"}, &config)
+		s.NoError(err)
+		s.Equal(Config{Database: Database{Host: "tenant.internal"}}, config)
+
+		err = schema.Fetch(tenantSource{}, &config)
+		s.EqualError(err, "required env APP_DB_HOST is not set")
+	})
+
+	s.Run("allocates nested pointers the same way as FetchEnv", func() {
+		config := Config{Replica: &Database{}}
+		err := schema.Fetch(tenantSource{"APP_DB_HOST": "a", "APP_REPLICA_HOST": "b", "APP_DEBUG": "true"}, &config)
+		s.NoError(err)
+		s.Equal("b", config.Replica.Host)
+
+		config = Config{}
+		err = schema.Fetch(tenantSource{"APP_DB_HOST": "a", "APP_REPLICA_HOST": "b", "APP_DEBUG": "true"}, &config)
+		s.NoError(err)
+		s.Equal(&Database{Host: "b"}, config.Replica)
+
+		config = Config{}
+		err = schema.Fetch(tenantSource{"APP_DB_HOST": "a", "APP_DEBUG": "true"}, &config)
+		s.NoError(err)
+		s.Nil(config.Replica)
+	})
+
+	s.Run("selects the profile from the source of each fetch", func() {
+		type Config struct {
+			Host string `tag:"host"`
+		}
+
+		env := env
+		env.ProfileEnv = "APP_PROFILE"
+
+		schema, err := env.Compile(Config{})
+		s.NoError(err)
+
+		values := map[string]string{"APP_DEV_HOST": "devhost", "APP_PROD_HOST": "prodhost"}
+
+		dev := tenantSource{"APP_PROFILE": "dev"}
+		prod := tenantSource{"APP_PROFILE": "prod"}
+		for name, value := range values {
+			dev[name] = value
+			prod[name] = value
+		}
+
+		var config Config
+		err = schema.Fetch(dev, &config)
+		s.NoError(err)
+		s.Equal("devhost", config.Host)
+
+		err = schema.Fetch(prod, &config)
+		s.NoError(err)
+		s.Equal("prodhost", config.Host)
+	})
+
+	s.Run("fails to fetch into a different struct", func() {
+		var other struct{}
+		err := schema.Fetch(nil, &other)
+		s.EqualError(err, "failed to fetch env into *struct {}, the schema was compiled for *envstruct_test.Config")
+	})
+
+	s.Run("fails to compile invalid tags", func() {
+		_, err := env.Compile(struct {
+			A string `tag:"a"`
+			B string `tag:"a"`
+		}{})
+		s.EqualError(err, "env APP_A is used by both fields A and B")
+	})
+}
+
+func BenchmarkSchemaFetch(b *testing.B) {
+	type Nested struct {
+		A string `env:"a"`
+		B int    `env:"b"`
+		C bool   `env:"c"`
+		D string `env:"d|e"`
+	}
+
+	type Config struct {
+		N0 Nested `env:"n0"`
+		N1 Nested `env:"n1"`
+		N2 Nested `env:"n2"`
+		N3 Nested `env:"n3"`
+	}
+
+	source := tenantSource{"APP_N0_B": "1", "APP_N3_E": "value"}
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "env",
+		Sources: []envstruct.Source{source},
+
+		Parser: envstruct.Parser{Unmarshaler: envstruct.Unmarshal},
+	}
+
+	schema, err := env.Compile(Config{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("FetchEnv", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var config Config
+			err := env.FetchEnv(&config)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Schema", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var config Config
+			err := schema.Fetch(nil, &config)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// SourceSecretsManager is the name of the SecretsManager Source
+const SourceSecretsManager = "secretsmanager"
+
+// SecretsManagerClient is the part of an AWS Secrets Manager client that the
+// SecretsManager Source needs, so that envstruct does not depend on the SDK.
+// The client is responsible for authenticating, for ex. with the IAM role of
+// the instance.
+//
+// GetSecretValue returns the SecretString of the current version of the
+// secret with the ID, which is its name or ARN.
+type SecretsManagerClient interface {
+	GetSecretValue(secretID string) (string, error)
+}
+
+// SecretsManager is a Source for a single AWS Secrets Manager secret that
+// holds a JSON object, the common pattern of keeping one secret per service.
+// Each key of the object is exposed as an env, uppercased and flattened the
+// same way as a JSONFile, so {"db_password": "..."} is looked up as
+// DB_PASSWORD. If the Prefix is set, it is prepended to every key, so that the
+// keys line up with the env names of the struct, for ex. APP_DB_PASSWORD.
+//
+// The secret is fetched again on Reload, so a Watcher picks up rotations.
+type SecretsManager struct {
+	// Client is the client used to fetch the secret
+	Client SecretsManagerClient
+
+	// SecretID is the name or ARN of the secret
+	SecretID string
+
+	// Prefix is optional and if set, is prepended to the name of every key
+	Prefix string
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// ReadSecretsManagerSecret fetches the secret with the ID, exposing its keys
+// with the prefix (if any) prepended
+func ReadSecretsManagerSecret(client SecretsManagerClient, secretID string, prefix string) (*SecretsManager, error) {
+	secret := &SecretsManager{
+		Client:   client,
+		SecretID: secretID,
+		Prefix:   prefix,
+	}
+
+	err := secret.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// Name returns SourceSecretsManager
+func (s *SecretsManager) Name() string { return SourceSecretsManager }
+
+// Lookup returns the value of the key of the secret
+func (s *SecretsManager) Lookup(envName string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, found := s.values[envName]
+	return value, found
+}
+
+// Files returns nothing as the secret is not read from a file
+func (s *SecretsManager) Files() []string { return nil }
+
+// Reload fetches the secret again. If it fails, the secret fetched before is
+// kept.
+func (s *SecretsManager) Reload() error {
+	secret, err := s.Client.GetSecretValue(s.SecretID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch secret %s: %w", s.SecretID, err)
+	}
+
+	var parsed map[string]interface{}
+	err = json.Unmarshal([]byte(secret), &parsed)
+	if err != nil {
+		return fmt.Errorf("failed to parse secret %s, it needs to hold a JSON object: %w", s.SecretID, err)
+	}
+
+	values := map[string]string{}
+	flatten(values, flatName(s.Prefix), parsed)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = values
+
+	return nil
+}
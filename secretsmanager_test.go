@@ -0,0 +1,79 @@
+package envstruct_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+// fakeSecretsManager holds the SecretString of each secret by its ID
+type fakeSecretsManager map[string]string
+
+func (f fakeSecretsManager) GetSecretValue(secretID string) (string, error) {
+	secret, found := f[secretID]
+	if !found {
+		return "", errors.New("ResourceNotFoundException")
+	}
+
+	return secret, nil
+}
+
+func (s *EnvstructSuite) TestSecretsManager() {
+	type Config struct {
+		Database struct {
+			Host     string `tag:"host"`
+			Password string `tag:"password,secret"`
+		} `tag:"db"`
+		Tokens []string `tag:"tokens"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	client := fakeSecretsManager{
+		"prod/app": `{"db": {"password": "hunter2"}, "tokens": ["a", "b"]}`,
+		"plain":    "hunter2",
+	}
+
+	s.Run("exposes the keys of the secret as envs", func() {
+		secret, err := envstruct.ReadSecretsManagerSecret(client, "prod/app", "app")
+		s.NoError(err)
+
+		env.Sources = []envstruct.Source{envstruct.Environment, secret}
+
+		os.Setenv("APP_DB_HOST", "localhost")
+
+		var config Config
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("localhost", config.Database.Host)
+		s.Equal("hunter2", config.Database.Password)
+		s.Equal([]string{"a", "b"}, config.Tokens)
+
+		client["prod/app"] = `{"db": {"password": "rotated"}}`
+		s.NoError(secret.Reload())
+
+		value, found := secret.Lookup("APP_DB_PASSWORD")
+		s.True(found)
+		s.Equal("rotated", value)
+	})
+
+	s.Run("fails when the secret is not a JSON object", func() {
+		_, err := envstruct.ReadSecretsManagerSecret(client, "plain", "")
+		s.EqualError(err, "failed to parse secret plain, it needs to hold a JSON object: invalid character 'h' looking for beginning of value")
+	})
+
+	s.Run("fails when the secret can not be fetched", func() {
+		_, err := envstruct.ReadSecretsManagerSecret(client, "missing", "")
+		s.EqualError(err, "failed to fetch secret missing: ResourceNotFoundException")
+	})
+}
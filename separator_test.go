@@ -0,0 +1,65 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestSeparator() {
+	type Config struct {
+		Limits    map[string]int    `tag:"limits"`
+		Endpoints map[string]string `tag:"endpoints,sep=="`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("separates keys and values with the field separator", func() {
+		os.Clearenv()
+		os.Setenv("APP_LIMITS", "a:1,b:2")
+		os.Setenv("APP_ENDPOINTS", "api=https://example.com:443")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Limits:    map[string]int{"a": 1, "b": 2},
+			Endpoints: map[string]string{"api": "https://example.com:443"},
+		}, config)
+	})
+
+	s.Run("separates keys and values with the parser separator", func() {
+		os.Clearenv()
+		os.Setenv("APP_LIMITS", "a=1,b=2")
+
+		env := env
+		env.Parser.Separator = "="
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(map[string]int{"a": 1, "b": 2}, config.Limits)
+	})
+
+	s.Run("joins defaults with the separator of the field", func() {
+		os.Clearenv()
+
+		config := Config{Endpoints: map[string]string{"api": "https://example.com"}}
+		script, err := env.ExportScript(&config, false)
+		s.NoError(err)
+		s.Contains(script, "export APP_ENDPOINTS='api=https://example.com'\n")
+	})
+}
@@ -0,0 +1,179 @@
+package envstruct
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// isStructSlice returns true if the type is a slice of structs, or of pointers
+// to structs, whose elements are fetched from indexed envs, for ex.
+// PREFIX_UPSTREAMS_0_HOST, rather than from a single delimited env.
+func isStructSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	return elem.Kind() == reflect.Struct && !isValueStruct(elem)
+}
+
+// extractSlice will extract the fields of each element of the slice of
+// structs, with the index of the element added onto the env names and the path
+// of its fields. When fetching, the slice is grown to fit every element that
+// has an env set within the sources, in which case a field is added for the
+// slice itself so that it is replaced by the grown slice once the plan is
+// applied. Elements that already exist within the slice are fetched into in
+// place, so that any defaults they hold are kept.
+func (e Envstruct) extractSlice(fields *[]*field, envNameBuilders [][]string, path []string, index []int, sliceValue reflect.Value) error {
+	elements := sliceValue
+	grown := false
+
+	if e.growCollections {
+		count, err := e.countElements(envNameBuilders, path, sliceValue.Type().Elem(), sliceValue.Len())
+		if err != nil {
+			return err
+		}
+
+		if count > sliceValue.Len() {
+			elements = reflect.MakeSlice(sliceValue.Type(), count, count)
+			reflect.Copy(elements, sliceValue)
+			grown = true
+
+			*fields = append(*fields, &field{
+				path:     strings.Join(path, "."),
+				index:    index,
+				elements: elements,
+				value:    sliceValue,
+			})
+		}
+	}
+
+	var errs Errors
+	for i := 0; i < elements.Len(); i++ {
+		element := elements.Index(i)
+		if element.Kind() == reflect.Ptr {
+			if element.IsNil() {
+				// Nil elements can only be allocated within a grown slice, as the
+				// slice on the struct is left untouched until the plan is applied
+				if !grown {
+					continue
+				}
+
+				element.Set(reflect.New(element.Type().Elem()))
+			}
+
+			element = element.Elem()
+		}
+
+		err := e.extractStruct(fields, appendElement(envNameBuilders, i), appendElementPath(path, i), index, element)
+		if err != nil {
+			if !e.BestEffort {
+				return err
+			}
+
+			errs = errs.collect(err)
+		}
+	}
+
+	return errs.errorOrNil()
+}
+
+// countElements returns the number of elements that the slice needs to hold.
+// Starting after the elements that it already has, each index is probed by
+// looking up the envs of every field of the element, and counting stops at the
+// first index that has none of them set (or overridden).
+func (e Envstruct) countElements(envNameBuilders [][]string, path []string, elemType reflect.Type, length int) (int, error) {
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	for i := length; ; i++ {
+		var probe []*field
+		err := e.extractStruct(&probe, appendElement(envNameBuilders, i), appendElementPath(path, i), nil, reflect.New(elemType).Elem())
+		if err != nil {
+			return 0, err
+		}
+
+		found, err := e.anySet(probe)
+		if err != nil {
+			return 0, err
+		}
+
+		if !found {
+			return i, nil
+		}
+	}
+}
+
+// anySet returns true if any of the fields has one of its envs set, or has
+// been pinned to a value through the Overrides
+func (e Envstruct) anySet(fields []*field) (bool, error) {
+	for _, f := range fields {
+		// A nested slice, map or pointer that has been grown or allocated has
+		// fields with envs set
+		if f.elements.IsValid() {
+			return true, nil
+		}
+
+		if _, ok := e.Overrides[f.path]; ok {
+			return true, nil
+		}
+
+		for _, envName := range f.envNames {
+			value, _, err := e.lookup(envName, f.sources, nil)
+			if err != nil {
+				return false, err
+			}
+
+			if value != "" {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// appendElement appends the index of the element onto each of the env names
+// being built up, for ex. PREFIX_UPSTREAMS becomes PREFIX_UPSTREAMS_0
+func appendElement(envNameBuilders [][]string, i int) [][]string {
+	return appendAliases(envNameBuilders, []string{strconv.Itoa(i)})
+}
+
+// appendElementPath appends the index of the element onto the path, for ex.
+// "Upstreams" becomes "Upstreams.0"
+func appendElementPath(path []string, i int) []string {
+	return append(path[:len(path):len(path)], strconv.Itoa(i))
+}
+
+// hasDynamicFields returns true if the struct type holds a slice or map of
+// structs, or a pointer to a struct, anywhere within it, in which case the
+// fields within it depend on the envs that are set and cannot be compiled
+// ahead of time.
+func hasDynamicFields(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if t.Kind() != reflect.Struct || isValueStruct(t) || visited[t] {
+		return false
+	}
+
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i).Type
+		if isStructSlice(fieldType) || isStructMap(fieldType) || isStructPointer(fieldType) || hasDynamicFields(fieldType, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isStructPointer returns true if the type is a pointer to a struct whose
+// fields are fetched from envs of their own
+func isStructPointer(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct && !isValueStruct(t.Elem())
+}
@@ -0,0 +1,139 @@
+package envstruct_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestStructSlices() {
+	type Upstream struct {
+		Host string   `tag:"host"`
+		Port int      `tag:"port"`
+		Tags []string `tag:"tags"`
+	}
+
+	type Route struct {
+		Path      string     `tag:"path"`
+		Upstreams []Upstream `tag:"upstreams"`
+	}
+
+	type Config struct {
+		Upstreams []Upstream  `tag:"upstreams"`
+		Backups   []*Upstream `tag:"backups"`
+		Routes    []Route     `tag:"routes"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("fetches each element from indexed envs", func() {
+		os.Clearenv()
+		os.Setenv("APP_UPSTREAMS_0_HOST", "a")
+		os.Setenv("APP_UPSTREAMS_0_PORT", "1")
+		os.Setenv("APP_UPSTREAMS_0_TAGS", "x,y")
+		os.Setenv("APP_UPSTREAMS_1_HOST", "b")
+		os.Setenv("APP_BACKUPS_0_PORT", "2")
+		os.Setenv("APP_ROUTES_0_PATH", "/api")
+		os.Setenv("APP_ROUTES_0_UPSTREAMS_0_HOST", "c")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Upstreams: []Upstream{{Host: "a", Port: 1, Tags: []string{"x", "y"}}, {Host: "b"}},
+			Backups:   []*Upstream{{Port: 2}},
+			Routes:    []Route{{Path: "/api", Upstreams: []Upstream{{Host: "c"}}}},
+		}, config)
+	})
+
+	s.Run("stops at the first index without any envs", func() {
+		os.Clearenv()
+		os.Setenv("APP_UPSTREAMS_0_HOST", "a")
+		os.Setenv("APP_UPSTREAMS_2_HOST", "c")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal([]Upstream{{Host: "a"}}, config.Upstreams)
+	})
+
+	s.Run("keeps the defaults of existing elements", func() {
+		os.Clearenv()
+		os.Setenv("APP_UPSTREAMS_0_HOST", "a")
+		os.Setenv("APP_UPSTREAMS_1_HOST", "b")
+
+		config := Config{Upstreams: []Upstream{{Host: "default", Port: 80}}}
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal([]Upstream{{Host: "a", Port: 80}, {Host: "b"}}, config.Upstreams)
+	})
+
+	s.Run("leaves the slice untouched when no envs are set", func() {
+		os.Clearenv()
+
+		config := Config{Upstreams: []Upstream{{Host: "default"}}}
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal([]Upstream{{Host: "default"}}, config.Upstreams)
+	})
+
+	s.Run("leaves the struct untouched when an element fails", func() {
+		os.Clearenv()
+		os.Setenv("APP_UPSTREAMS_0_HOST", "a")
+		os.Setenv("APP_UPSTREAMS_1_PORT", "abc")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.Error(err)
+
+		var fieldErr *envstruct.FieldError
+		s.True(errors.As(err, &fieldErr))
+		s.Equal("Upstreams.1.Port", fieldErr.Path)
+		s.Equal("APP_UPSTREAMS_1_PORT", fieldErr.Env)
+
+		s.Nil(config.Upstreams)
+	})
+
+	s.Run("grows elements through the overrides", func() {
+		os.Clearenv()
+
+		env := env
+		env.Overrides = map[string]string{"Upstreams.0.Host": "a"}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal([]Upstream{{Host: "a"}}, config.Upstreams)
+	})
+
+	s.Run("fetches through a schema", func() {
+		os.Clearenv()
+		os.Setenv("APP_UPSTREAMS_0_HOST", "a")
+
+		schema, err := env.Compile(Config{})
+		s.NoError(err)
+
+		var config Config
+		err = schema.Fetch(nil, &config)
+		s.NoError(err)
+
+		s.Equal([]Upstream{{Host: "a"}}, config.Upstreams)
+	})
+}
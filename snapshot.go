@@ -0,0 +1,256 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// SourceSnapshot is the name of the Snapshot Source
+const SourceSnapshot = "snapshot"
+
+// Snapshot is the fully resolved configuration of a struct, holding the raw
+// value of every env that was fetched along with where it came from. It can be
+// written to disk and used as a Source to boot from when the remote sources
+// can not be reached, see FetchEnvWithSnapshot.
+type Snapshot struct {
+	// Time is when the snapshot was taken
+	Time time.Time `json:"time"`
+
+	// Profile is the profile that was selected through the ProfileEnv when
+	// the snapshot was taken, if any, so that the same profile is used when
+	// booting from it
+	Profile string `json:"profile,omitempty"`
+
+	// Entries are the fetched envs, keyed by the name of the env
+	Entries map[string]SnapshotEntry `json:"entries"`
+}
+
+// SnapshotEntry is a single env within a Snapshot
+type SnapshotEntry struct {
+	// Field is the path to the field within the struct
+	Field string `json:"field"`
+
+	// Source is the name of the source that the env was fetched from
+	Source string `json:"source"`
+
+	// Value is the raw value of the env, encrypted if it is a secret and the
+	// snapshot was written with a public key
+	Value string `json:"value"`
+
+	// Secret is true if the field has the "secret" tag option
+	Secret bool `json:"secret,omitempty"`
+}
+
+// SnapshotFile is where a Snapshot is kept on disk
+type SnapshotFile struct {
+	// Path is the path of the snapshot file
+	Path string
+
+	// PublicKey is optional and if set, the values of secrets are encrypted
+	// with it when the snapshot is written, using the same encryption as .env
+	// files (see GenerateDotenvKeys)
+	PublicKey string
+
+	// PrivateKey is used to decrypt the values of secrets when the snapshot is
+	// read, if they were encrypted
+	PrivateKey string
+}
+
+// Snapshot fetches the envs for the struct without setting them on it and
+// returns the resolved configuration.
+func (e Envstruct) Snapshot(object interface{}) (*Snapshot, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to snapshot object, needs to be type struct")
+	}
+
+	p, err := e.resolve(object)
+	if err != nil && !e.BestEffort {
+		return nil, err
+	}
+
+	return e.snapshot(p)
+}
+
+// snapshot builds the snapshot from the plan. Values set through the Overrides
+// are left out, as they are not fetched from a source. The entries are keyed by
+// the env name of the field, even when the value was looked up under another
+// name within its source, as the snapshot is only asked for the env names when
+// booting from it. Fields without any env names are left out for that reason.
+// The plan holds the fields within nil pointers and grown collections, so it
+// is used rather than the struct, which does not have them yet.
+func (e Envstruct) snapshot(p *plan) (*Snapshot, error) {
+	profile, err := e.activeProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		Time:    time.Now().UTC(),
+		Profile: profile,
+		Entries: map[string]SnapshotEntry{},
+	}
+
+	for _, a := range p.assignments {
+		if a.source == "" || a.source == SourceOverride || a.source == SourceDefault {
+			continue
+		}
+
+		if a.envName == "" {
+			continue
+		}
+
+		snapshot.Entries[a.envName] = SnapshotEntry{
+			Field:  a.path,
+			Source: a.source,
+			Value:  a.raw,
+			Secret: a.options.secret,
+		}
+	}
+
+	return snapshot, nil
+}
+
+// FetchEnvWithSnapshot will fetch the envs into the struct and, if it
+// succeeds, write the resolved configuration to the snapshot file. If a source
+// fails to be read, for ex. because a remote source could not be reached, the
+// envs are fetched from the last snapshot instead so that the application can
+// still start. Only the snapshot is used in that case, not any of the Sources,
+// and the profile is the one that was selected when the snapshot was taken.
+// Any other error, such as a value that fails to parse or a required env that
+// is not set, is returned as it is, as is the error of the source if there is
+// no snapshot to fall back to.
+func (e Envstruct) FetchEnvWithSnapshot(object interface{}, file SnapshotFile) error {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return errors.New("failed to parse env into object, needs to be type struct")
+	}
+
+	start := time.Now()
+
+	p, err := e.fetchEnv(object)
+	e.observe(start, p, err)
+
+	if err == nil {
+		snapshot, err := e.snapshot(p)
+		if err != nil {
+			return err
+		}
+
+		return snapshot.Write(file)
+	}
+
+	var sourceErr *SourceError
+	if !errors.As(err, &sourceErr) {
+		return err
+	}
+
+	snapshot, snapshotErr := ReadSnapshot(file)
+	if snapshotErr != nil {
+		return fmt.Errorf("%w (and could not fall back to the snapshot: %v)", err, snapshotErr)
+	}
+
+	fallback := e
+	fallback.Sources = []Source{snapshot}
+	fallback.SourceTagName = ""
+	fallback.SourceNameTags = nil
+
+	// The profile is pinned to the one the snapshot was taken with, as the
+	// ProfileEnv can not be looked up within the snapshot
+	if e.ProfileEnv != "" && snapshot.Profile != "" {
+		fallback.Sources = append(fallback.Sources, valuesSource{e.ProfileEnv: snapshot.Profile})
+	}
+
+	return fallback.FetchEnv(object)
+}
+
+// Write writes the snapshot to the file, encrypting the values of secrets if
+// the file has a PublicKey. The file is replaced atomically so that a crash
+// while writing does not leave behind a broken snapshot.
+func (s *Snapshot) Write(file SnapshotFile) error {
+	written := Snapshot{Time: s.Time, Profile: s.Profile, Entries: map[string]SnapshotEntry{}}
+	for name, entry := range s.Entries {
+		if entry.Secret && file.PublicKey != "" && !isEncryptedDotenvValue(entry.Value) {
+			encrypted, err := EncryptDotenvValue(file.PublicKey, entry.Value)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", name, err)
+			}
+
+			entry.Value = encrypted
+		}
+
+		written.Entries[name] = entry
+	}
+
+	contents, err := json.MarshalIndent(written, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(file.Path), filepath.Base(file.Path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	_, err = temp.Write(contents)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	err = os.Rename(temp.Name(), file.Path)
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ReadSnapshot reads the snapshot from the file, decrypting the values of
+// secrets with the PrivateKey of the file
+func ReadSnapshot(file SnapshotFile) (*Snapshot, error) {
+	contents, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	err = json.Unmarshal(contents, &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", file.Path, err)
+	}
+
+	for name, entry := range snapshot.Entries {
+		if !isEncryptedDotenvValue(entry.Value) {
+			continue
+		}
+
+		if file.PrivateKey == "" {
+			return nil, fmt.Errorf("snapshot %s contains encrypted values but no private key was set", file.Path)
+		}
+
+		entry.Value, err = decryptDotenvValue(file.PrivateKey, entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s in snapshot %s: %w", name, file.Path, err)
+		}
+
+		snapshot.Entries[name] = entry
+	}
+
+	return &snapshot, nil
+}
+
+// Name returns SourceSnapshot
+func (s *Snapshot) Name() string { return SourceSnapshot }
+
+// Lookup returns the value of the env within the snapshot
+func (s *Snapshot) Lookup(envName string) (string, bool) {
+	entry, found := s.Entries[envName]
+	return entry.Value, found
+}
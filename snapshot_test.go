@@ -0,0 +1,239 @@
+package envstruct_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+// remoteSource is a source that can be made unreachable
+type remoteSource struct {
+	mapSource
+	unreachable bool
+}
+
+func (r *remoteSource) Name() string { return "remote" }
+
+func (r *remoteSource) LookupErr(envName string) (string, bool, error) {
+	if r.unreachable {
+		return "", false, errors.New("connection refused")
+	}
+
+	value, found := r.mapSource.Lookup(envName)
+	return value, found, nil
+}
+
+func (s *EnvstructSuite) TestSnapshot() {
+	type Config struct {
+		Host     string   `tag:"host"`
+		Hosts    []string `tag:"hosts"`
+		Password string   `tag:"password,secret"`
+		Pinned   string
+	}
+
+	defer os.Clearenv()
+
+	publicKey, privateKey, err := envstruct.GenerateDotenvKeys()
+	s.NoError(err)
+
+	remote := &remoteSource{mapSource: mapSource{"PREFIX_PASSWORD": "hunter2"}}
+
+	env := envstruct.Envstruct{
+		Prefix:    "prefix",
+		TagName:   "tag",
+		Sources:   []envstruct.Source{envstruct.Environment, remote},
+		Overrides: map[string]string{"Pinned": "pinned"},
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	file := envstruct.SnapshotFile{
+		Path:       filepath.Join(s.T().TempDir(), "snapshot.json"),
+		PublicKey:  publicKey,
+		PrivateKey: privateKey,
+	}
+
+	s.Run("takes a snapshot with the provenance of each env", func() {
+		os.Setenv("PREFIX_HOST", "example.com")
+
+		snapshot, err := env.Snapshot(&Config{})
+		s.NoError(err)
+
+		s.Equal(map[string]envstruct.SnapshotEntry{
+			"PREFIX_HOST":     {Field: "Host", Source: envstruct.SourceEnv, Value: "example.com"},
+			"PREFIX_PASSWORD": {Field: "Password", Source: "remote", Value: "hunter2", Secret: true},
+		}, snapshot.Entries)
+	})
+
+	s.Run("writes the snapshot when the fetch succeeds", func() {
+		os.Setenv("PREFIX_HOSTS", "a,b")
+
+		var config Config
+		err := env.FetchEnvWithSnapshot(&config, file)
+		s.NoError(err)
+		s.Equal("hunter2", config.Password)
+
+		contents, err := os.ReadFile(file.Path)
+		s.NoError(err)
+		s.NotContains(string(contents), "hunter2")
+		s.Contains(string(contents), "example.com")
+	})
+
+	s.Run("boots from the snapshot when a source is unreachable", func() {
+		os.Clearenv()
+		remote.unreachable = true
+
+		var config Config
+		err := env.FetchEnvWithSnapshot(&config, file)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Host:     "example.com",
+			Hosts:    []string{"a", "b"},
+			Password: "hunter2",
+			Pinned:   "pinned",
+		}, config)
+	})
+
+	s.Run("returns the error when there is no snapshot", func() {
+		missing := envstruct.SnapshotFile{Path: filepath.Join(s.T().TempDir(), "missing.json")}
+
+		var config Config
+		err := env.FetchEnvWithSnapshot(&config, missing)
+		s.EqualError(err, "failed to look up PREFIX_HOST in source remote: connection refused (and could not fall back to the snapshot: failed to read snapshot: open "+missing.Path+": no such file or directory)")
+	})
+
+	s.Run("needs the private key to read encrypted secrets", func() {
+		_, err := envstruct.ReadSnapshot(envstruct.SnapshotFile{Path: file.Path})
+		s.EqualError(err, "snapshot "+file.Path+" contains encrypted values but no private key was set")
+	})
+
+	s.Run("returns errors other than unreachable sources", func() {
+		os.Clearenv()
+		os.Setenv("PREFIX_HOSTS", `"a`)
+		remote.unreachable = false
+
+		var config Config
+		err := env.FetchEnvWithSnapshot(&config, file)
+		s.EqualError(err, "field Hosts (env PREFIX_HOSTS): quoted item \"a is missing its closing quote")
+		s.Nil(config.Hosts)
+	})
+
+	s.Run("boots with the profile that the snapshot was taken with", func() {
+		os.Clearenv()
+		os.Setenv("PREFIX_PROFILE", "prod")
+		os.Setenv("PREFIX_PROD_HOST", "prod.example.com")
+		os.Setenv("PREFIX_DEV_HOST", "dev.example.com")
+		remote.unreachable = false
+
+		env := env
+		env.ProfileEnv = "PREFIX_PROFILE"
+
+		file := envstruct.SnapshotFile{Path: filepath.Join(s.T().TempDir(), "snapshot.json")}
+
+		var config Config
+		err := env.FetchEnvWithSnapshot(&config, file)
+		s.NoError(err)
+
+		os.Setenv("PREFIX_PROFILE", "dev")
+		remote.unreachable = true
+
+		config = Config{}
+		err = env.FetchEnvWithSnapshot(&config, file)
+		s.NoError(err)
+		s.Equal("prod.example.com", config.Host)
+	})
+
+	s.Run("takes a snapshot of the fields behind nil pointers", func() {
+		type Database struct {
+			Host string `tag:"host"`
+		}
+
+		type Config struct {
+			Name     string    `tag:"name"`
+			Database *Database `tag:"db"`
+		}
+
+		os.Clearenv()
+		os.Setenv("PREFIX_NAME", "app")
+		os.Setenv("PREFIX_DB_HOST", "localhost")
+
+		env := env
+		env.Sources = nil
+		env.Overrides = nil
+
+		snapshot, err := env.Snapshot(&Config{})
+		s.NoError(err)
+
+		s.Equal(map[string]envstruct.SnapshotEntry{
+			"PREFIX_NAME":    {Field: "Name", Source: envstruct.SourceEnv, Value: "app"},
+			"PREFIX_DB_HOST": {Field: "Database.Host", Source: envstruct.SourceEnv, Value: "localhost"},
+		}, snapshot.Entries)
+	})
+
+	s.Run("takes a snapshot of the fields within slices of structs", func() {
+		type Upstream struct {
+			Host string `tag:"host"`
+			Port int    `tag:"port"`
+		}
+
+		type Config struct {
+			Upstreams []Upstream `tag:"upstreams"`
+		}
+
+		os.Clearenv()
+		os.Setenv("PREFIX_UPSTREAMS_0_HOST", "a")
+		os.Setenv("PREFIX_UPSTREAMS_0_PORT", "1")
+		os.Setenv("PREFIX_UPSTREAMS_1_HOST", "b")
+
+		env := env
+		env.Sources = nil
+		env.Overrides = nil
+
+		snapshot, err := env.Snapshot(&Config{})
+		s.NoError(err)
+
+		s.Equal(map[string]envstruct.SnapshotEntry{
+			"PREFIX_UPSTREAMS_0_HOST": {Field: "Upstreams.0.Host", Source: envstruct.SourceEnv, Value: "a"},
+			"PREFIX_UPSTREAMS_0_PORT": {Field: "Upstreams.0.Port", Source: envstruct.SourceEnv, Value: "1"},
+			"PREFIX_UPSTREAMS_1_HOST": {Field: "Upstreams.1.Host", Source: envstruct.SourceEnv, Value: "b"},
+		}, snapshot.Entries)
+	})
+
+	s.Run("restores the fields that are named differently within their source", func() {
+		type Config struct {
+			Host     string `tag:"host"`
+			Password string `tag:"password,secret" remote:"secret/db#password"`
+		}
+
+		os.Clearenv()
+		os.Setenv("PREFIX_HOST", "example.com")
+
+		remote := &remoteSource{mapSource: mapSource{"secret/db#password": "hunter2"}}
+
+		env := env
+		env.Sources = []envstruct.Source{envstruct.Environment, remote}
+		env.SourceNameTags = []string{"remote"}
+		env.Overrides = nil
+
+		file := envstruct.SnapshotFile{Path: filepath.Join(s.T().TempDir(), "snapshot.json")}
+
+		var config Config
+		err := env.FetchEnvWithSnapshot(&config, file)
+		s.NoError(err)
+
+		snapshot, err := envstruct.ReadSnapshot(file)
+		s.NoError(err)
+		s.Equal(envstruct.SnapshotEntry{Field: "Password", Source: "remote", Value: "hunter2", Secret: true}, snapshot.Entries["PREFIX_PASSWORD"])
+
+		remote.unreachable = true
+
+		config = Config{}
+		err = env.FetchEnvWithSnapshot(&config, file)
+		s.NoError(err)
+		s.Equal(Config{Host: "example.com", Password: "hunter2"}, config)
+	})
+}
@@ -0,0 +1,310 @@
+package envstruct
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Source is somewhere that the values of envs can be looked up from, for ex.
+// the environment of the process or the services bound to a Cloud Foundry
+// app. It needs to be safe to call concurrently.
+type Source interface {
+	// Name is the name of the source, used as the key of FetchEvent.Sources
+	Name() string
+
+	// Lookup returns the value of the env and true if the source has it set
+	Lookup(envName string) (string, bool)
+}
+
+// FileSource is implemented by sources that are read from files, so that the
+// files can be watched and the source read again when they change. The
+// Watcher reloads every FileSource before it fetches the envs again.
+type FileSource interface {
+	Source
+
+	// Files returns the files or directories that the source is read from
+	Files() []string
+
+	// Reload reads the files again
+	Reload() error
+}
+
+// FallibleSource is implemented by sources that can fail to look up an env,
+// for ex. because a remote service could not be reached. The error fails the
+// fetch, rather than the env being treated as not set.
+type FallibleSource interface {
+	Source
+
+	// LookupErr returns the value of the env and true if the source has it
+	// set, or an error if the source failed to look it up
+	LookupErr(envName string) (string, bool, error)
+}
+
+// ListableSource is implemented by sources that can list the names of the
+// envs that they hold, so that the keys of maps of structs can be found
+// within them. The Environment, the file sources and the Document are
+// listable, as are the indexes of every IndexedSource.
+type ListableSource interface {
+	Source
+
+	// Names returns the name of every env that the source holds
+	Names() []string
+}
+
+// Environment is the Source for the environment of the process. It is the
+// default when no Sources are set.
+var Environment Source = environment{}
+
+type environment struct{}
+
+func (environment) Name() string { return SourceEnv }
+
+func (environment) Lookup(envName string) (string, bool) {
+	return os.LookupEnv(envName)
+}
+
+func (environment) Names() []string {
+	environ := os.Environ()
+
+	names := make([]string, 0, len(environ))
+	for _, env := range environ {
+		if name, _, _ := strings.Cut(env, "="); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// LookupFunc returns a Source with the name that looks up envs with the
+// function, which has the same signature as os.LookupEnv. It is the simplest
+// way of plugging an alternative lookup into the Sources, for ex. a fake
+// environment in tests.
+func LookupFunc(name string, lookup func(envName string) (string, bool)) Source {
+	return lookupFunc{name: name, lookup: lookup}
+}
+
+type lookupFunc struct {
+	name   string
+	lookup func(envName string) (string, bool)
+}
+
+func (l lookupFunc) Name() string { return l.name }
+
+func (l lookupFunc) Lookup(envName string) (string, bool) {
+	return l.lookup(envName)
+}
+
+// sources returns the Sources, defaulting to the Environment
+func (e Envstruct) sources() []Source {
+	if len(e.Sources) == 0 {
+		return []Source{Environment}
+	}
+
+	return e.Sources
+}
+
+// hasSource returns true if one of the sources has the name
+func (e Envstruct) hasSource(name string) bool {
+	for _, source := range e.sources() {
+		if source.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lookup will look up the env in each of the sources in order, returning the
+// value along with the name of the source that it was found in. Envs that are
+// set to an empty string are treated as not set. If any sources are pinned,
+// only the sources with those names are used. The names are the names to look
+// the env up under within each source, keyed by the name of the source, which
+// are used instead of the env name. Sources are skipped if the env has no name
+// within them.
+func (e Envstruct) lookup(envName string, pinned []string, names map[string]string) (string, string, error) {
+	for _, source := range e.sources() {
+		if len(pinned) > 0 && !containsString(pinned, source.Name()) {
+			continue
+		}
+
+		name := envName
+		if sourceName, ok := names[source.Name()]; ok {
+			name = sourceName
+		}
+
+		if name == "" {
+			continue
+		}
+
+		value, found, err := lookupSource(source, name)
+		if err != nil {
+			return "", "", &SourceError{Source: source.Name(), Name: name, Err: err}
+		}
+
+		if found && value != "" {
+			return value, source.Name(), nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// SourceError is returned when a source fails to be read, for ex. because a
+// remote service can not be reached, as opposed to a value that was read but
+// is invalid.
+type SourceError struct {
+	// Source is the name of the source that failed
+	Source string
+
+	// Name is the name that was being looked up within the source, or empty
+	// if the source failed to be indexed
+	Name string
+
+	// Err is the error returned by the source
+	Err error
+}
+
+func (e *SourceError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("failed to index source %s: %v", e.Source, e.Err)
+	}
+
+	return fmt.Sprintf("failed to look up %s in source %s: %v", e.Name, e.Source, e.Err)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// lookupSource will look up the env within the source, returning the error if
+// it is a FallibleSource
+func lookupSource(source Source, envName string) (string, bool, error) {
+	if fallible, ok := source.(FallibleSource); ok {
+		return fallible.LookupErr(envName)
+	}
+
+	value, found := source.Lookup(envName)
+	return value, found, nil
+}
+
+// reloadSources will read each FileSource again
+func (e Envstruct) reloadSources() error {
+	for _, source := range e.Sources {
+		if fileSource, ok := source.(FileSource); ok {
+			err := fileSource.Reload()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sourceFiles returns the files of each FileSource
+func (e Envstruct) sourceFiles() []string {
+	var files []string
+	for _, source := range e.Sources {
+		if fileSource, ok := source.(FileSource); ok {
+			files = append(files, fileSource.Files()...)
+		}
+	}
+
+	return files
+}
+
+// MapNames returns a Source that looks up each env within the source under the
+// name returned by the mapping, so that the struct tags can stay the same for
+// every source while each source is looked up with its own naming, for ex.
+// PREFIX_DB_HOST as "secret/data/app/db#host" within a secret store. An env is
+// not looked up within the source if the mapping returns an empty string.
+func MapNames(source Source, mapping func(envName string) string) Source {
+	return mappedSource{source: source, mapping: mapping}
+}
+
+type mappedSource struct {
+	source  Source
+	mapping func(envName string) string
+}
+
+func (m mappedSource) Name() string { return m.source.Name() }
+
+func (m mappedSource) Lookup(envName string) (string, bool) {
+	name := m.mapping(envName)
+	if name == "" {
+		return "", false
+	}
+
+	return m.source.Lookup(name)
+}
+
+func (m mappedSource) LookupErr(envName string) (string, bool, error) {
+	name := m.mapping(envName)
+	if name == "" {
+		return "", false, nil
+	}
+
+	return lookupSource(m.source, name)
+}
+
+func (m mappedSource) Files() []string {
+	if fileSource, ok := m.source.(FileSource); ok {
+		return fileSource.Files()
+	}
+
+	return nil
+}
+
+func (m mappedSource) Reload() error {
+	if fileSource, ok := m.source.(FileSource); ok {
+		return fileSource.Reload()
+	}
+
+	return nil
+}
+
+// expandSourceNames returns the names of the field within the sources, with
+// any references to other envs, for ex. ${APP_ENV}, replaced by their values
+func (e Envstruct) expandSourceNames(f *field) (map[string]string, error) {
+	if len(f.sourceNames) == 0 {
+		return nil, nil
+	}
+
+	names := make(map[string]string, len(f.sourceNames))
+	for source, name := range f.sourceNames {
+		var err error
+		names[source] = os.Expand(name, func(envName string) string {
+			if err != nil {
+				return ""
+			}
+
+			var value string
+			value, _, err = e.lookup(envName, nil, nil)
+			if err == nil && value == "" {
+				err = fmt.Errorf("env %s referenced by the %s name of field %s is not set", envName, source, f.path)
+			}
+
+			return value
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
+
+// mapNames returns the keys of the values in order
+func mapNames(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
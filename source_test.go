@@ -0,0 +1,184 @@
+package envstruct_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type mapSource map[string]string
+
+func (m mapSource) Name() string { return "map" }
+
+func (m mapSource) Lookup(envName string) (string, bool) {
+	value, found := m[envName]
+	return value, found
+}
+
+func (s *EnvstructSuite) TestSources() {
+	type Config struct {
+		Field1 string `tag:"field1"`
+		Field2 string `tag:"field2"`
+		Field3 string `tag:"field3"`
+	}
+
+	defer os.Clearenv()
+
+	s.Run("looks up each env in the sources in order", func() {
+		observer := &recordingObserver{}
+		env := envstruct.Envstruct{
+			Prefix:   "prefix",
+			TagName:  "tag",
+			Observer: observer,
+			Sources: []envstruct.Source{
+				envstruct.Environment,
+				mapSource{"PREFIX_FIELD1": "from map", "PREFIX_FIELD2": "from map", "PREFIX_FIELD3": ""},
+			},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		os.Setenv("PREFIX_FIELD1", "from env")
+
+		config := Config{Field3: "default"}
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Field1: "from env",
+			Field2: "from map",
+			Field3: "default",
+		}, config)
+
+		s.Equal(map[string]int{envstruct.SourceEnv: 1, "map": 1}, observer.events[0].Sources)
+	})
+
+	s.Run("does not use the environment if it is not one of the sources", func() {
+		env := envstruct.Envstruct{
+			Prefix:  "prefix",
+			TagName: "tag",
+			Sources: []envstruct.Source{mapSource{"PREFIX_FIELD2": "from map"}},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		os.Setenv("PREFIX_FIELD1", "from env")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Field2: "from map"}, config)
+	})
+
+	s.Run("looks up envs with a function", func() {
+		observer := &recordingObserver{}
+		env := envstruct.Envstruct{
+			Prefix:   "prefix",
+			TagName:  "tag",
+			Observer: observer,
+			Sources: []envstruct.Source{
+				envstruct.LookupFunc("fake", func(envName string) (string, bool) {
+					return strings.ToLower(envName), envName != "PREFIX_FIELD3"
+				}),
+			},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Field1: "prefix_field1", Field2: "prefix_field2"}, config)
+		s.Equal(map[string]int{"fake": 2}, observer.events[0].Sources)
+	})
+}
+
+func (s *EnvstructSuite) TestMapNames() {
+	type Config struct {
+		Database struct {
+			Host     string `tag:"host"`
+			Password string `tag:"password"`
+		} `tag:"db"`
+	}
+
+	defer os.Clearenv()
+
+	secrets := mapSource{"secret/data/app/db#password": "hunter2"}
+
+	env := envstruct.Envstruct{
+		Prefix:  "prefix",
+		TagName: "tag",
+		Sources: []envstruct.Source{
+			envstruct.Environment,
+			envstruct.MapNames(secrets, func(envName string) string {
+				// Only the passwords are kept within the secret store
+				if !strings.HasSuffix(envName, "_PASSWORD") {
+					return ""
+				}
+
+				path := strings.Split(strings.ToLower(strings.TrimPrefix(envName, "PREFIX_")), "_")
+				return "secret/data/app/" + strings.Join(path[:len(path)-1], "/") + "#" + path[len(path)-1]
+			}),
+		},
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	os.Setenv("PREFIX_DB_HOST", "localhost")
+
+	var config Config
+	err := env.FetchEnv(&config)
+	s.NoError(err)
+
+	s.Equal("localhost", config.Database.Host)
+	s.Equal("hunter2", config.Database.Password)
+}
+
+func (s *EnvstructSuite) TestSourceTag() {
+	type Config struct {
+		Host     string `tag:"host" source:"env"`
+		Password string `tag:"password" source:"map"`
+		Token    string `tag:"token" source:"env, map"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:        "prefix",
+		TagName:       "tag",
+		SourceTagName: "source",
+		Sources: []envstruct.Source{
+			envstruct.Environment,
+			mapSource{"PREFIX_HOST": "from map", "PREFIX_PASSWORD": "from map", "PREFIX_TOKEN": "from map"},
+		},
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("only fetches fields from the sources they are pinned to", func() {
+		os.Setenv("PREFIX_PASSWORD", "from env")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{
+			Host:     "",
+			Password: "from map",
+			Token:    "from map",
+		}, config)
+	})
+
+	s.Run("fails if the field is pinned to a source that is not configured", func() {
+		env := env
+		env.Sources = nil
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field Password is pinned to source map, which is not one of the sources")
+	})
+}
@@ -0,0 +1,96 @@
+package envstruct
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// FieldSpec describes a single field of a struct that is fetched from the
+// env, for tooling such as docs generators and deployment manifests
+type FieldSpec struct {
+	// Path is the path to the field within the struct, for ex. "Database.Host"
+	Path string `json:"path"`
+
+	// Group is the path to the nested struct that the field is within, for ex.
+	// "Database", or empty for fields on the struct itself
+	Group string `json:"group,omitempty"`
+
+	// EnvNames are the names of the envs that the field is fetched from, in
+	// order of precedence
+	EnvNames []string `json:"env_names"`
+
+	// Type is the Go type of the field, for ex. "time.Duration"
+	Type string `json:"type"`
+
+	// Default is the default from the DefaultTagName, or otherwise the value
+	// already set on the field, formatted the same way as it would be written
+	// in the env. It is empty if there is no default, and redacted if the
+	// field is a secret.
+	Default string `json:"default,omitempty"`
+
+	// Required is true if FetchEnv fails when none of the envs are set
+	Required bool `json:"required"`
+
+	// Secret is true if the field has the "secret" tag option
+	Secret bool `json:"secret"`
+
+	// Description is the description of the field from the
+	// DescriptionTagName
+	Description string `json:"description,omitempty"`
+}
+
+// Spec returns a FieldSpec for every field of the struct that is fetched from
+// the env, in the order that they are declared. The values already set on the
+// struct are used as the defaults. It is the same information that Help
+// renders, for tools that need it in a structured form.
+func (e Envstruct) Spec(object interface{}) ([]FieldSpec, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to build spec for object, needs to be type struct")
+	}
+
+	fields, err := e.fields(object)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := []FieldSpec{}
+	for _, f := range fields {
+		if !f.tagged || len(f.envNames) == 0 {
+			continue
+		}
+
+		specs = append(specs, e.fieldSpec(f))
+	}
+
+	return specs, nil
+}
+
+// fieldSpec returns the FieldSpec of the field
+func (e Envstruct) fieldSpec(f *field) FieldSpec {
+	defaultValue, hasDefault := e.defaultValue(f)
+
+	spec := FieldSpec{
+		Path:        f.path,
+		EnvNames:    f.envNames,
+		Type:        f.description.Type.String(),
+		Required:    e.required(f) && !hasDefault,
+		Secret:      f.options.secret,
+		Description: e.description(f),
+	}
+
+	if i := strings.LastIndex(f.path, "."); i != -1 {
+		spec.Group = f.path[:i]
+	}
+
+	switch {
+	case (hasDefault || !f.value.IsZero()) && f.options.secret:
+		spec.Default = redacted
+	case hasDefault:
+		spec.Default = defaultValue
+	case !f.value.IsZero():
+		spec.Default = formatValue(f.value, e.fieldParser(f.options))
+	}
+
+	return spec
+}
@@ -0,0 +1,73 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestSpec() {
+	type Config struct {
+		Port     int    `tag:"port" desc:"listen port"`
+		Password string `tag:"password,secret"`
+		Database struct {
+			Host    string        `tag:"host|addr,optional" desc:"database host"`
+			Timeout time.Duration `tag:"timeout"`
+		} `tag:"db"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:     "app",
+		TagName:    "tag",
+		RequireAll: true,
+	}
+
+	config := Config{Port: 8080, Password: "hunter2"}
+	config.Database.Timeout = 5 * time.Second
+
+	s.Run("describes every field fetched from the env", func() {
+		spec, err := env.Spec(&config)
+		s.NoError(err)
+
+		s.Equal([]envstruct.FieldSpec{
+			{
+				Path:        "Port",
+				EnvNames:    []string{"APP_PORT"},
+				Type:        "int",
+				Default:     "8080",
+				Required:    true,
+				Description: "listen port",
+			},
+			{
+				Path:     "Password",
+				EnvNames: []string{"APP_PASSWORD"},
+				Type:     "string",
+				Default:  "******",
+				Required: true,
+				Secret:   true,
+			},
+			{
+				Path:        "Database.Host",
+				Group:       "Database",
+				EnvNames:    []string{"APP_DB_HOST", "APP_DB_ADDR"},
+				Type:        "string",
+				Description: "database host",
+			},
+			{
+				Path:     "Database.Timeout",
+				Group:    "Database",
+				EnvNames: []string{"APP_DB_TIMEOUT"},
+				Type:     "time.Duration",
+				Default:  "5s",
+				Required: true,
+			},
+		}, spec)
+	})
+
+	s.Run("fails when the object is not a struct", func() {
+		var port int
+		_, err := env.Spec(&port)
+		s.EqualError(err, "failed to build spec for object, needs to be type struct")
+	})
+}
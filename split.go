@@ -0,0 +1,92 @@
+package envstruct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// item is a single item of a slice or map as it is written within the env
+type item struct {
+	value string
+
+	// quoted is true if the item was wrapped in double quotes, in which case
+	// its value is kept exactly as it was written
+	quoted bool
+}
+
+// splitItems splits the value into its items on the delimiter. Items can be
+// wrapped in double quotes the same way as the fields of a CSV record (RFC
+// 4180), in which case they can contain the delimiter, and a double quote is
+// written as two double quotes, for ex. `"Doe, Jane",Smith` has the items
+// "Doe, Jane" and "Smith".
+func splitItems(value string, delimiter string) ([]item, error) {
+	var items []item
+	for {
+		trimmed := strings.TrimLeft(value, " \t")
+		if !strings.HasPrefix(trimmed, `"`) {
+			i := strings.Index(value, delimiter)
+			if i == -1 {
+				return append(items, item{value: value}), nil
+			}
+
+			items = append(items, item{value: value[:i]})
+			value = value[i+len(delimiter):]
+
+			continue
+		}
+
+		unquoted, rest, err := unquoteItem(trimmed)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item{value: unquoted, quoted: true})
+
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			return items, nil
+		}
+
+		if !strings.HasPrefix(rest, delimiter) {
+			return nil, fmt.Errorf("unexpected %q after quoted item %q", rest, unquoted)
+		}
+
+		value = rest[len(delimiter):]
+	}
+}
+
+// unquoteItem returns the value within the double quotes that the value
+// starts with, along with the rest of the value after the closing quote
+func unquoteItem(value string) (string, string, error) {
+	var unquoted strings.Builder
+
+	rest := value[1:]
+	for {
+		i := strings.Index(rest, `"`)
+		if i == -1 {
+			return "", "", fmt.Errorf("quoted item %s is missing its closing quote", value)
+		}
+
+		unquoted.WriteString(rest[:i])
+		rest = rest[i+1:]
+
+		// Two double quotes within a quoted item are a double quote
+		if !strings.HasPrefix(rest, `"`) {
+			return unquoted.String(), rest, nil
+		}
+
+		unquoted.WriteString(`"`)
+		rest = rest[1:]
+	}
+}
+
+// quoteItem wraps the item in double quotes if it could not be split back out
+// of the value otherwise, because it contains the delimiter or starts with a
+// double quote
+func quoteItem(value string, delimiter string) string {
+	if !strings.Contains(value, delimiter) && !strings.HasPrefix(strings.TrimLeft(value, " \t"), `"`) {
+		return value
+	}
+
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
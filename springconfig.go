@@ -0,0 +1,199 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SourceSpringCloudConfig is the name of the SpringCloudConfig Source
+const SourceSpringCloudConfig = "spring_cloud_config"
+
+// springIndex matches the index of a list element within a Spring property
+// key, for ex. the "[0]" of "servers[0]"
+var springIndex = regexp.MustCompile(`\[(\d+)\]$`)
+
+// SpringCloudConfig is a Source for a Spring Cloud Config server, so that Go
+// services can share the config server of JVM services. The properties of
+// every property source returned for the application, profile and label are
+// flattened the same way as a PropertiesFile, with the first property source
+// taking precedence as it does in Spring. Lists written with indexes, for ex.
+// "servers[0]" and "servers[1]", are joined with a comma.
+//
+// The configuration is fetched again on Reload, so a Watcher picks up any
+// changes, for ex. after a refresh is triggered through its ReloadHandler.
+type SpringCloudConfig struct {
+	// URI is the address of the config server, for ex.
+	// "http://config.example.com:8888"
+	URI string
+
+	// Application is the name of the application
+	Application string
+
+	// Profile is the profile of the configuration, or a comma separated list
+	// of profiles. It is defaulted to "default".
+	Profile string
+
+	// Label is optional and if set, is the label of the configuration, for ex.
+	// the git branch
+	Label string
+
+	// Username and Password are optional and if set, are used to authenticate
+	// with the config server using basic auth
+	Username string
+	Password string
+
+	// Client is the HTTP client used to talk to the config server, defaulted
+	// to http.DefaultClient
+	Client *http.Client
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// ReadSpringCloudConfig fetches the configuration of the application from the
+// config server at the URI. The label is optional.
+func ReadSpringCloudConfig(uri string, application string, profile string, label string) (*SpringCloudConfig, error) {
+	config := &SpringCloudConfig{
+		URI:         uri,
+		Application: application,
+		Profile:     profile,
+		Label:       label,
+	}
+
+	err := config.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Name returns SourceSpringCloudConfig
+func (c *SpringCloudConfig) Name() string { return SourceSpringCloudConfig }
+
+// Lookup returns the value of the env within the configuration
+func (c *SpringCloudConfig) Lookup(envName string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	value, found := c.values[envName]
+	return value, found
+}
+
+// Files returns nothing as the configuration is not read from files
+func (c *SpringCloudConfig) Files() []string { return nil }
+
+// Reload fetches the configuration from the config server again. If it fails,
+// the configuration fetched before is kept.
+func (c *SpringCloudConfig) Reload() error {
+	profile := c.Profile
+	if profile == "" {
+		profile = "default"
+	}
+
+	path := []string{url.PathEscape(c.Application), url.PathEscape(profile)}
+	if c.Label != "" {
+		// Spring Cloud Config expects slashes within labels to be written as
+		// "(_)"
+		path = append(path, url.PathEscape(strings.ReplaceAll(c.Label, "/", "(_)")))
+	}
+
+	request, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.URI, "/")+"/"+strings.Join(path, "/"), nil)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Accept", "application/json")
+	if c.Username != "" || c.Password != "" {
+		request.SetBasicAuth(c.Username, c.Password)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to fetch spring cloud config: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch spring cloud config: %s", response.Status)
+	}
+
+	var environment struct {
+		PropertySources []struct {
+			Name   string                 `json:"name"`
+			Source map[string]interface{} `json:"source"`
+		} `json:"propertySources"`
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&environment)
+	if err != nil {
+		return fmt.Errorf("failed to parse spring cloud config: %w", err)
+	}
+
+	values := map[string]string{}
+
+	// The property sources are in order of precedence, so they are applied in
+	// reverse for the earlier ones to win
+	for i := len(environment.PropertySources) - 1; i >= 0; i-- {
+		for name, value := range flattenSpringProperties(environment.PropertySources[i].Source) {
+			values[name] = value
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values = values
+
+	return nil
+}
+
+// flattenSpringProperties converts the keys of the properties into env names,
+// joining the elements of lists written with indexes with a comma
+func flattenSpringProperties(properties map[string]interface{}) map[string]string {
+	values := map[string]string{}
+
+	type element struct {
+		index int
+		value string
+	}
+	lists := map[string][]element{}
+
+	for key, value := range properties {
+		if match := springIndex.FindStringSubmatchIndex(key); match != nil {
+			index, err := strconv.Atoi(key[match[2]:match[3]])
+			if err == nil {
+				name := flatName(key[:match[0]])
+				lists[name] = append(lists[name], element{index, flatValue(value)})
+				continue
+			}
+		}
+
+		values[flatName(key)] = flatValue(value)
+	}
+
+	for name, elements := range lists {
+		sort.Slice(elements, func(i, j int) bool { return elements[i].index < elements[j].index })
+
+		list := make([]string, len(elements))
+		for i, elem := range elements {
+			list[i] = elem.value
+		}
+
+		values[name] = strings.Join(list, ",")
+	}
+
+	return values
+}
@@ -0,0 +1,89 @@
+package envstruct_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestSpringCloudConfig() {
+	type Config struct {
+		Database struct {
+			Host     string   `tag:"host"`
+			Port     int      `tag:"port"`
+			Replicas []string `tag:"replicas"`
+		} `tag:"db"`
+		Debug bool `tag:"debug"`
+	}
+
+	defer os.Clearenv()
+
+	debug := "true"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		if user != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != "/app/dev,cloud/feature(_)x" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{
+  "name": "app",
+  "profiles": ["dev", "cloud"],
+  "label": "feature/x",
+  "propertySources": [
+    {"name": "app-dev.yml", "source": {"db.host": "dev.example.com", "db.replicas[1]": "b", "db.replicas[0]": "a", "debug": ` + debug + `}},
+    {"name": "application.yml", "source": {"db.host": "localhost", "db.port": 5432, "debug": false}}
+  ]
+}`))
+	}))
+	defer server.Close()
+
+	s.Run("fetches and flattens the property sources", func() {
+		config := &envstruct.SpringCloudConfig{
+			URI:         server.URL + "/",
+			Application: "app",
+			Profile:     "dev,cloud",
+			Label:       "feature/x",
+			Username:    "user",
+			Password:    "pass",
+		}
+		s.NoError(config.Reload())
+
+		env := envstruct.Envstruct{
+			TagName: "tag",
+			Sources: []envstruct.Source{envstruct.Environment, config},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var fetched Config
+		watcher, err := envstruct.NewWatcher(env, &fetched)
+		s.NoError(err)
+
+		s.Equal("dev.example.com", fetched.Database.Host)
+		s.Equal(5432, fetched.Database.Port)
+		s.Equal([]string{"a", "b"}, fetched.Database.Replicas)
+		s.True(fetched.Debug)
+
+		s.Run("fetches the configuration again on reload", func() {
+			debug = "false"
+
+			changes, err := watcher.Reload()
+			s.NoError(err)
+			s.Equal([]envstruct.Change{{Name: "DEBUG", Old: true, New: false}}, changes)
+		})
+	})
+
+	s.Run("fails when the config can not be fetched", func() {
+		_, err := envstruct.ReadSpringCloudConfig(server.URL, "app", "", "")
+		s.EqualError(err, "failed to fetch spring cloud config: 401 Unauthorized")
+	})
+}
@@ -0,0 +1,102 @@
+package envstruct
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// SourceSQL is the name of the SQLTable Source
+const SourceSQL = "sql"
+
+// DefaultSQLQuery is the query used by the SQLTable Source if none is set
+const DefaultSQLQuery = "SELECT name, value FROM settings"
+
+// SQLTable is a Source for settings stored as rows within a database table,
+// so that applications keeping their settings in their primary database can
+// populate their structs the same way as from the environment. The query
+// needs to return two columns, the name of the env and its value. Rows with a
+// NULL value are treated as not set.
+//
+// The rows are read again on Reload, so a Watcher picks up any changes.
+type SQLTable struct {
+	// DB is the database to query
+	DB *sql.DB
+
+	// Query is the query that returns the name and value of each env,
+	// defaulted to DefaultSQLQuery. For ex.
+	// "SELECT key, value FROM config WHERE app = 'billing'".
+	Query string
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// ReadSQLTable reads the rows returned by the query. If the query is empty,
+// DefaultSQLQuery is used.
+func ReadSQLTable(db *sql.DB, query string) (*SQLTable, error) {
+	table := &SQLTable{DB: db, Query: query}
+
+	err := table.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// Name returns SourceSQL
+func (t *SQLTable) Name() string { return SourceSQL }
+
+// Lookup returns the value of the env within the table
+func (t *SQLTable) Lookup(envName string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	value, found := t.values[envName]
+	return value, found
+}
+
+// Files returns nothing as the settings are not read from files
+func (t *SQLTable) Files() []string { return nil }
+
+// Reload reads the rows again. If it fails, the rows read before are kept.
+func (t *SQLTable) Reload() error {
+	query := t.Query
+	if query == "" {
+		query = DefaultSQLQuery
+	}
+
+	rows, err := t.DB.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to query settings: %w", err)
+	}
+	defer rows.Close()
+
+	values := map[string]string{}
+	for rows.Next() {
+		var name string
+		var value sql.NullString
+
+		err = rows.Scan(&name, &value)
+		if err != nil {
+			return fmt.Errorf("failed to read settings: %w", err)
+		}
+
+		if value.Valid {
+			values[name] = value.String
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.values = values
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package envstruct_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+// fakeSQLDriver is a database/sql driver that answers every query with the
+// rows of the table named by the DSN
+type fakeSQLDriver struct{}
+
+var (
+	fakeSQLTablesMu sync.Mutex
+	fakeSQLTables   = map[string][][]driver.Value{}
+)
+
+func init() {
+	sql.Register("envstruct-fake", fakeSQLDriver{})
+}
+
+func (fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	return fakeSQLConn{table: name}, nil
+}
+
+type fakeSQLConn struct{ table string }
+
+func (c fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeSQLStmt{table: c.table, query: query}, nil
+}
+func (fakeSQLConn) Close() error              { return nil }
+func (fakeSQLConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeSQLStmt struct {
+	table string
+	query string
+}
+
+func (fakeSQLStmt) Close() error  { return nil }
+func (fakeSQLStmt) NumInput() int { return 0 }
+func (fakeSQLStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s fakeSQLStmt) Query([]driver.Value) (driver.Rows, error) {
+	if s.query != envstruct.DefaultSQLQuery {
+		return nil, errors.New("syntax error")
+	}
+
+	fakeSQLTablesMu.Lock()
+	defer fakeSQLTablesMu.Unlock()
+
+	return &fakeSQLRows{rows: append([][]driver.Value{}, fakeSQLTables[s.table]...)}, nil
+}
+
+type fakeSQLRows struct{ rows [][]driver.Value }
+
+func (*fakeSQLRows) Columns() []string { return []string{"name", "value"} }
+func (*fakeSQLRows) Close() error      { return nil }
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+func (s *EnvstructSuite) TestSQLTable() {
+	type Config struct {
+		Host  string `tag:"host"`
+		Port  int    `tag:"port"`
+		Debug bool   `tag:"debug"`
+	}
+
+	defer os.Clearenv()
+
+	fakeSQLTablesMu.Lock()
+	fakeSQLTables["settings"] = [][]driver.Value{
+		{"APP_HOST", "localhost"},
+		{"APP_PORT", "5432"},
+		{"APP_DEBUG", nil},
+	}
+	fakeSQLTablesMu.Unlock()
+
+	db, err := sql.Open("envstruct-fake", "settings")
+	s.NoError(err)
+	defer db.Close()
+
+	s.Run("reads the rows returned by the query", func() {
+		os.Setenv("APP_PORT", "6543")
+
+		table, err := envstruct.ReadSQLTable(db, "")
+		s.NoError(err)
+
+		env := envstruct.Envstruct{
+			Prefix:  "app",
+			TagName: "tag",
+			Sources: []envstruct.Source{envstruct.Environment, table},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+		s.Equal(Config{Host: "localhost", Port: 6543}, config)
+
+		s.Run("reads the rows again on reload", func() {
+			fakeSQLTablesMu.Lock()
+			fakeSQLTables["settings"] = append(fakeSQLTables["settings"], []driver.Value{"APP_DEBUG", "true"})
+			fakeSQLTablesMu.Unlock()
+
+			changes, err := watcher.Reload()
+			s.NoError(err)
+			s.Equal([]envstruct.Change{{Name: "APP_DEBUG", Old: false, New: true}}, changes)
+		})
+	})
+
+	s.Run("fails if the query fails", func() {
+		_, err := envstruct.ReadSQLTable(db, "SELECT")
+		s.EqualError(err, "failed to query settings: syntax error")
+	})
+}
@@ -0,0 +1,189 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// subscription is a callback subscribed to the changes of some of the fields
+// of a Watcher, which are coalesced until no more changes arrive for the
+// debounce duration
+type subscription struct {
+	// envNames are the names of the envs of the subscribed fields
+	envNames map[string]bool
+
+	debounce time.Duration
+	callback func([]Change)
+
+	mu      sync.Mutex
+	pending []change
+	timer   *time.Timer
+	stopped bool
+}
+
+// Subscribe subscribes the callback to the changes of the fields at the paths,
+// for ex. "Database.Host", or every field within a nested struct such as
+// "Database". Changes made by reloads within the debounce duration of each
+// other are coalesced into a single notification, with the old value from
+// before the first reload and the new value from the last one. This allows a
+// subsystem to only be rebuilt when its own configuration settles, rather
+// than on every reload.
+//
+// The callback is called within its own goroutine once the debounce duration
+// has passed, or within the goroutine that called Reload if it is zero. The
+// returned function unsubscribes the callback, dropping any pending changes.
+func (w *Watcher) Subscribe(debounce time.Duration, callback func([]Change), paths ...string) (func(), error) {
+	w.mu.RLock()
+	fields, err := w.env.fields(w.object)
+	w.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &subscription{
+		envNames: map[string]bool{},
+		debounce: debounce,
+		callback: callback,
+	}
+
+	for _, path := range paths {
+		found := false
+		for _, f := range fields {
+			if f.tagged && len(f.envNames) > 0 && (f.path == path || strings.HasPrefix(f.path, path+".")) {
+				s.envNames[f.envNames[0]] = true
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("failed to subscribe to %s, there is no field at the path", path)
+		}
+	}
+
+	w.subscriptionsMu.Lock()
+	w.subscriptions = append(w.subscriptions, s)
+	w.subscriptionsMu.Unlock()
+
+	return func() {
+		w.subscriptionsMu.Lock()
+		for i, subscribed := range w.subscriptions {
+			if subscribed == s {
+				w.subscriptions = append(w.subscriptions[:i:i], w.subscriptions[i+1:]...)
+				break
+			}
+		}
+		w.subscriptionsMu.Unlock()
+
+		s.stop()
+	}, nil
+}
+
+// notifySubscriptions passes the changes on to each of the subscriptions to
+// the changed fields
+func (w *Watcher) notifySubscriptions(changes []change) {
+	w.subscriptionsMu.Lock()
+	subscriptions := append([]*subscription(nil), w.subscriptions...)
+	w.subscriptionsMu.Unlock()
+
+	for _, s := range subscriptions {
+		var subscribed []change
+		for _, c := range changes {
+			if s.envNames[c.Name] {
+				subscribed = append(subscribed, c)
+			}
+		}
+
+		if len(subscribed) > 0 {
+			s.add(subscribed)
+		}
+	}
+}
+
+// add coalesces the changes with the pending ones and restarts the debounce.
+// A field that is changed back to its old value is dropped from the pending
+// changes, as it did not change overall. The values are compared before they
+// are redacted, so that a secret that is rotated twice is not mistaken for one
+// that is changed back.
+func (s *subscription) add(changes []change) {
+	s.mu.Lock()
+
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+
+	for _, c := range changes {
+		merged := false
+		for i, pending := range s.pending {
+			if pending.Name == c.Name {
+				if reflect.DeepEqual(pending.old, c.new) {
+					s.pending = append(s.pending[:i:i], s.pending[i+1:]...)
+				} else {
+					s.pending[i].New = c.New
+					s.pending[i].new = c.new
+				}
+
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			s.pending = append(s.pending, c)
+		}
+	}
+
+	if s.debounce <= 0 {
+		s.mu.Unlock()
+		s.flush()
+		return
+	}
+
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.debounce, s.flush)
+	} else {
+		s.timer.Reset(s.debounce)
+	}
+
+	s.mu.Unlock()
+}
+
+// flush calls the callback with the pending changes
+func (s *subscription) flush() {
+	s.mu.Lock()
+
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	changes := make([]Change, len(pending))
+	for i, c := range pending {
+		changes[i] = c.Change
+	}
+
+	s.callback(changes)
+}
+
+// stop drops any pending changes and stops the debounce
+func (s *subscription) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	s.pending = nil
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
@@ -0,0 +1,179 @@
+package envstruct_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestSubscribe() {
+	type Config struct {
+		Database struct {
+			Host string `tag:"host"`
+			Port int    `tag:"port"`
+		} `tag:"db"`
+		Debug bool `tag:"debug"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("coalesces the changes of the subscribed fields", func() {
+		os.Clearenv()
+		os.Setenv("DB_HOST", "a")
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		notifications := make(chan []envstruct.Change, 10)
+		_, err = watcher.Subscribe(100*time.Millisecond, func(changes []envstruct.Change) {
+			notifications <- changes
+		}, "Database")
+		s.NoError(err)
+
+		os.Setenv("DB_HOST", "b")
+		_, err = watcher.Reload()
+		s.NoError(err)
+
+		os.Setenv("DB_HOST", "c")
+		os.Setenv("DB_PORT", "5432")
+		os.Setenv("DEBUG", "true")
+		_, err = watcher.Reload()
+		s.NoError(err)
+
+		select {
+		case changes := <-notifications:
+			s.Equal([]envstruct.Change{
+				{Name: "DB_HOST", Old: "a", New: "c"},
+				{Name: "DB_PORT", Old: 0, New: 5432},
+			}, changes)
+		case <-time.After(5 * time.Second):
+			s.Fail("timed out waiting for the notification")
+		}
+
+		s.Never(func() bool { return len(notifications) > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+	})
+
+	s.Run("drops the fields that are changed back to their old value", func() {
+		os.Clearenv()
+		os.Setenv("DB_HOST", "a")
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		notifications := make(chan []envstruct.Change, 10)
+		_, err = watcher.Subscribe(100*time.Millisecond, func(changes []envstruct.Change) {
+			notifications <- changes
+		}, "Database")
+		s.NoError(err)
+
+		os.Setenv("DB_HOST", "b")
+		os.Setenv("DB_PORT", "5432")
+		_, err = watcher.Reload()
+		s.NoError(err)
+
+		os.Setenv("DB_HOST", "a")
+		_, err = watcher.Reload()
+		s.NoError(err)
+
+		select {
+		case changes := <-notifications:
+			s.Equal([]envstruct.Change{{Name: "DB_PORT", Old: 0, New: 5432}}, changes)
+		case <-time.After(5 * time.Second):
+			s.Fail("timed out waiting for the notification")
+		}
+
+		os.Setenv("DB_PORT", "5433")
+		_, err = watcher.Reload()
+		s.NoError(err)
+
+		os.Setenv("DB_PORT", "5432")
+		_, err = watcher.Reload()
+		s.NoError(err)
+
+		s.Never(func() bool { return len(notifications) > 0 }, 300*time.Millisecond, 10*time.Millisecond)
+	})
+
+	s.Run("notifies of a secret that is rotated twice", func() {
+		type Config struct {
+			Password string `tag:"password,secret"`
+		}
+
+		os.Clearenv()
+		os.Setenv("PASSWORD", "a")
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		notifications := make(chan []envstruct.Change, 10)
+		_, err = watcher.Subscribe(100*time.Millisecond, func(changes []envstruct.Change) {
+			notifications <- changes
+		}, "Password")
+		s.NoError(err)
+
+		os.Setenv("PASSWORD", "b")
+		_, err = watcher.Reload()
+		s.NoError(err)
+
+		os.Setenv("PASSWORD", "c")
+		_, err = watcher.Reload()
+		s.NoError(err)
+
+		select {
+		case changes := <-notifications:
+			s.Equal([]envstruct.Change{{Name: "PASSWORD", Old: "******", New: "******"}}, changes)
+		case <-time.After(5 * time.Second):
+			s.Fail("timed out waiting for the notification")
+		}
+	})
+
+	s.Run("notifies straight away without a debounce", func() {
+		os.Clearenv()
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		var notified [][]envstruct.Change
+		unsubscribe, err := watcher.Subscribe(0, func(changes []envstruct.Change) {
+			notified = append(notified, changes)
+		}, "Debug")
+		s.NoError(err)
+
+		os.Setenv("DB_HOST", "a")
+		_, err = watcher.Reload()
+		s.NoError(err)
+		s.Empty(notified)
+
+		os.Setenv("DEBUG", "true")
+		_, err = watcher.Reload()
+		s.NoError(err)
+		s.Equal([][]envstruct.Change{{{Name: "DEBUG", Old: false, New: true}}}, notified)
+
+		unsubscribe()
+
+		os.Setenv("DEBUG", "false")
+		_, err = watcher.Reload()
+		s.NoError(err)
+		s.Len(notified, 1)
+	})
+
+	s.Run("fails to subscribe to a path without fields", func() {
+		var config Config
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		_, err = watcher.Subscribe(0, func([]envstruct.Change) {}, "Data")
+		s.EqualError(err, "failed to subscribe to Data, there is no field at the path")
+	})
+}
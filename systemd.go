@@ -0,0 +1,57 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// systemdQuoter escapes the characters that have a meaning within the double
+// quotes of a systemd environment file
+var systemdQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`)
+
+// SystemdEnvironmentFile renders the current configuration of the struct as a
+// systemd environment file of `NAME=value` lines, to be referenced through
+// the EnvironmentFile= setting of a service deployed on a plain VM. Values
+// that contain anything other than plain characters are double quoted, which
+// also allows them to span multiple lines. Secrets are masked and commented
+// out so that they are not written to the file, unless withSecrets is true.
+// Fields that are nil pointers are left out.
+func (e Envstruct) SystemdEnvironmentFile(object interface{}, withSecrets bool) (string, error) {
+	if reflect.TypeOf(object).Elem().Kind() != reflect.Struct {
+		return "", errors.New("failed to render systemd environment file for object, needs to be type struct")
+	}
+
+	values, err := e.envValues(object)
+	if err != nil {
+		return "", err
+	}
+
+	var file strings.Builder
+	for _, v := range values {
+		if v.field.options.secret && !withSecrets {
+			if v.field.value.IsZero() {
+				fmt.Fprintf(&file, "# %s=\n", v.name)
+			} else {
+				fmt.Fprintf(&file, "# %s=%s\n", v.name, redacted)
+			}
+
+			continue
+		}
+
+		fmt.Fprintf(&file, "%s=%s\n", v.name, systemdQuote(v.value))
+	}
+
+	return file.String(), nil
+}
+
+// systemdQuote wraps the value in double quotes unless it only contains
+// characters that are read back the same without them
+func systemdQuote(value string) string {
+	if isPlainValue(value) {
+		return value
+	}
+
+	return `"` + systemdQuoter.Replace(value) + `"`
+}
@@ -0,0 +1,53 @@
+package envstruct_test
+
+import (
+	"time"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestSystemdEnvironmentFile() {
+	type Config struct {
+		Port     int           `tag:"port"`
+		Timeout  time.Duration `tag:"timeout"`
+		Greeting string        `tag:"greeting"`
+		Template string        `tag:"template"`
+		Password string        `tag:"password,secret"`
+		Token    string        `tag:"token,secret"`
+		Cert     *string       `tag:"cert"`
+		Internal string
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+	}
+
+	config := Config{
+		Port:     8080,
+		Timeout:  time.Minute,
+		Greeting: "say \"hi\"\nand bye",
+		Template: "$HOME/templates",
+		Password: "hunter2",
+	}
+
+	s.Run("renders each env with its value", func() {
+		file, err := env.SystemdEnvironmentFile(&config, false)
+		s.NoError(err)
+
+		s.Equal(`APP_PORT=8080
+APP_TIMEOUT=1m0s
+APP_GREETING="say \"hi\"
+and bye"
+APP_TEMPLATE="\$HOME/templates"
+# APP_PASSWORD=******
+# APP_TOKEN=
+`, file)
+	})
+
+	s.Run("includes secrets if asked to", func() {
+		file, err := env.SystemdEnvironmentFile(&config, true)
+		s.NoError(err)
+		s.Contains(file, "APP_PASSWORD=hunter2\nAPP_TOKEN=\n")
+	})
+}
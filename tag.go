@@ -0,0 +1,129 @@
+package envstruct
+
+import "strings"
+
+// tagOptions are the envstruct specific options that can be appended to the
+// tag value after a comma. For ex. `env:"field,optional"` will set the
+// optional option on the field.
+type tagOptions struct {
+	// optional opts the field out of being required when RequireAll is on
+	optional bool
+
+	// required makes FetchEnv fail if none of the envs of the field are set,
+	// even when RequireAll is off
+	required bool
+
+	// secret marks the field as holding a secret, which is redacted whenever
+	// the configuration is displayed
+	secret bool
+
+	// blob populates a nested struct from a single env holding a document,
+	// rather than from an env per field
+	blob bool
+
+	// file treats the value of the env as the path of a file, which is read
+	// and parsed in its place
+	file bool
+
+	// base64 decodes the value from base64 before it is parsed
+	base64 bool
+
+	// hex decodes the value from hex before it is parsed
+	hex bool
+
+	// json unmarshals the whole value as JSON, rather than splitting slices
+	// and maps or walking through nested structs
+	json bool
+
+	// format is the format that the value is written in, for ex.
+	// `env:"timeout,format=iso8601"` parses ISO 8601 durations
+	format string
+
+	// inline walks through a nested struct without adding its name onto the
+	// env names of the fields within it, as if they were declared on the
+	// parent struct
+	inline bool
+
+	// delimiter overrides the Delimiter of the Parser for the field, for ex.
+	// `env:"paths,delim=:"` splits the value on colons
+	delimiter string
+	// separator overrides the Separator of the Parser for the field, for ex.
+	// `env:"endpoints,sep=="` separates the keys and values of a map with an
+	// equals sign
+	separator string
+}
+
+// single returns true if a nested struct is fetched from a single env,
+// rather than an env per field
+func (o tagOptions) single() bool {
+	return o.blob || o.json
+}
+
+// tagOptionSetters sets each of the tag options, keyed by the name of the
+// option. Options that take a value are written as "name=value".
+var tagOptionSetters = map[string]func(options *tagOptions, value string){
+	"optional": func(options *tagOptions, _ string) { options.optional = true },
+	"required": func(options *tagOptions, _ string) { options.required = true },
+	"secret":   func(options *tagOptions, _ string) { options.secret = true },
+	"format":   func(options *tagOptions, value string) { options.format = value },
+	"blob":     func(options *tagOptions, _ string) { options.blob = true },
+	"file":     func(options *tagOptions, _ string) { options.file = true },
+	"base64":   func(options *tagOptions, _ string) { options.base64 = true },
+	"hex":      func(options *tagOptions, _ string) { options.hex = true },
+	"json":     func(options *tagOptions, _ string) { options.json = true },
+	"delim":    func(options *tagOptions, value string) { options.delimiter = value },
+	"sep":      func(options *tagOptions, value string) { options.separator = value },
+	"inline":   func(options *tagOptions, _ string) { options.inline = true },
+	"squash":   func(options *tagOptions, _ string) { options.inline = true },
+}
+
+// IsTagOption returns true if the option is one of the envstruct options that
+// can be appended to a tag value after a comma, for ex. "optional". It is
+// meant for tools that check struct tags.
+func IsTagOption(option string) bool {
+	name, _ := splitTagOption(option)
+
+	_, found := tagOptionSetters[name]
+	return found
+}
+
+// splitTagOption splits the option into its name and value
+func splitTagOption(option string) (string, string) {
+	option = strings.TrimSpace(option)
+
+	if i := strings.Index(option, "="); i != -1 {
+		return option[:i], option[i+1:]
+	}
+
+	return option, ""
+}
+
+// parseTagValue will split the tag value into the name that is used to build
+// up the env and the envstruct options that are appended to it. Any value
+// after a comma that is not an envstruct option is left within the name,
+// unless StripValue is on in which case it is removed.
+func (e Envstruct) parseTagValue(tagValue string) (string, tagOptions) {
+	var options tagOptions
+
+	values := strings.Split(tagValue, ",")
+
+	name := []string{values[0]}
+	for _, value := range values[1:] {
+		optionName, optionValue := splitTagOption(value)
+
+		setter, found := tagOptionSetters[optionName]
+		if !found {
+			name = append(name, value)
+			continue
+		}
+
+		setter(&options, optionValue)
+	}
+
+	// Removes any string after a comma within the tag value
+	if e.StripValue {
+		return name[0], options
+	}
+
+	return strings.Join(name, ","), options
+}
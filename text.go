@@ -0,0 +1,40 @@
+package envstruct
+
+import (
+	"encoding"
+	"reflect"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isTextUnmarshaler returns true if a pointer to the type implements
+// encoding.TextUnmarshaler, in which case the value is handed to UnmarshalText
+// as a whole rather than to the Unmarshaler. This lets types such as
+// netip.Addr or custom enums be fetched without any extra work.
+func isTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// unmarshalText will unmarshal the data into the value with UnmarshalText if
+// it implements encoding.TextUnmarshaler, allocating pointers to such types as
+// needed. False is returned if the value does not implement it.
+func unmarshalText(data []byte, v interface{}) (bool, error) {
+	if unmarshaler, ok := v.(encoding.TextUnmarshaler); ok {
+		return true, unmarshaler.UnmarshalText(data)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Ptr || !isTextUnmarshaler(rv.Elem().Type().Elem()) {
+		return false, nil
+	}
+
+	elem := reflect.New(rv.Elem().Type().Elem())
+	err := elem.Interface().(encoding.TextUnmarshaler).UnmarshalText(data)
+	if err != nil {
+		return true, err
+	}
+
+	rv.Elem().Set(elem)
+
+	return true, nil
+}
@@ -0,0 +1,76 @@
+package envstruct_test
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+
+	return nil
+}
+
+func (s *EnvstructSuite) TestTextUnmarshaler() {
+	type Config struct {
+		Addr    netip.Addr   `tag:"addr"`
+		Gateway *netip.Addr  `tag:"gateway"`
+		IP      net.IP       `tag:"ip"`
+		Peers   []netip.Addr `tag:"peers"`
+		Level   level        `tag:"level"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	s.Run("parses the value with UnmarshalText", func() {
+		os.Setenv("APP_ADDR", "10.0.0.1")
+		os.Setenv("APP_GATEWAY", "10.0.0.254")
+		os.Setenv("APP_IP", "::1")
+		os.Setenv("APP_PEERS", "10.0.0.2, 10.0.0.3")
+		os.Setenv("APP_LEVEL", "INFO")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		gateway := netip.MustParseAddr("10.0.0.254")
+		s.Equal(Config{
+			Addr:    netip.MustParseAddr("10.0.0.1"),
+			Gateway: &gateway,
+			IP:      net.ParseIP("::1"),
+			Peers:   []netip.Addr{netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("10.0.0.3")},
+			Level:   1,
+		}, config)
+	})
+
+	s.Run("returns the error from UnmarshalText", func() {
+		os.Clearenv()
+		os.Setenv("APP_LEVEL", "loud")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, `field Level (env APP_LEVEL): unknown level "loud"`)
+	})
+}
@@ -0,0 +1,92 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// timeLayouts are the layouts that are tried in order when parsing a
+// time.Time value. Layouts without an offset are interpreted in the Location
+// of the Parser. The last ones are the timestamp forms of YAML, with single
+// digit date and time fields, which were accepted through yaml.Unmarshal
+// before times were parsed by the Parser.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006-1-2T15:4:5.999999999Z07:00",
+	"2006-1-2t15:4:5.999999999Z07:00",
+	"2006-1-2 15:4:5.999999999",
+	"2006-1-2",
+}
+
+// isValueStruct returns true for struct types that hold a single value and
+// should be parsed as one, rather than traversed for nested fields.
+func isValueStruct(t reflect.Type) bool {
+	return t == timeType || isOptional(t) || isTextUnmarshaler(t)
+}
+
+// unmarshal will unmarshal the data into the value using the Unmarshaler,
+// except for time values which are parsed by the Parser so that the Location
+// can be applied, values that the format of the field applies to, and values
+// that implement encoding.TextUnmarshaler.
+func (p Parser) unmarshal(data []byte, v interface{}) error {
+	if p.format != "" {
+		handled, err := p.unmarshalFormat(data, v)
+		if handled || err != nil {
+			return err
+		}
+	}
+
+	switch target := v.(type) {
+	case *time.Time:
+		t, err := p.parseTime(string(data))
+		if err != nil {
+			return err
+		}
+
+		*target = t
+		return nil
+
+	case **time.Time:
+		t, err := p.parseTime(string(data))
+		if err != nil {
+			return err
+		}
+
+		*target = &t
+		return nil
+	}
+
+	handled, err := unmarshalText(data, v)
+	if handled || err != nil {
+		return err
+	}
+
+	return p.Unmarshaler(data, v)
+}
+
+// parseTime will parse the value using the first time layout that matches.
+// If the value does not contain an offset, it is interpreted in the Location
+// of the Parser, which defaults to UTC.
+func (p Parser) parseTime(value string) (time.Time, error) {
+	location := p.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, value, location)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("failed to parse %q as a time", value)
+}
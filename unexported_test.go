@@ -0,0 +1,58 @@
+package envstruct_test
+
+import (
+	"os"
+	"sync"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+type settings struct {
+	Level string `tag:"level"`
+}
+
+func (s *EnvstructSuite) TestUnexportedFields() {
+	type Config struct {
+		Host string `tag:"host"`
+		port int    `tag:"port"`
+		mu   sync.Mutex
+		settings
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:  "app",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	os.Setenv("APP_HOST", "localhost")
+	os.Setenv("APP_PORT", "8080")
+	os.Setenv("APP_LEVEL", "debug")
+
+	s.Run("skips unexported fields", func() {
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("localhost", config.Host)
+		s.Equal(0, config.port)
+		s.Equal("debug", config.Level)
+	})
+
+	s.Run("fails on tagged unexported fields when rejected", func() {
+		env := env
+		env.RejectUnexported = true
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "field port is unexported, so it cannot be set")
+		s.Equal("", config.Host)
+	})
+}
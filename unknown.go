@@ -0,0 +1,58 @@
+package envstruct
+
+import (
+	"sort"
+	"strings"
+)
+
+// unknownEnvs returns the names of the envs set within the sources that start
+// with one of the prefixes, but that none of the fields are fetched from. Only
+// the sources that can be listed are searched through.
+func (e Envstruct) unknownEnvs(object interface{}, fields []*field) []string {
+	envPrefixes := e.envPrefixes(object)
+	if len(envPrefixes) == 0 {
+		return nil
+	}
+
+	known := map[string]bool{e.ProfileEnv: true}
+	for _, f := range fields {
+		for _, envName := range append(f.envNames[:len(f.envNames):len(f.envNames)], f.deprecatedNames...) {
+			known[envName] = true
+			if e.FileSuffix != "" {
+				known[envName+e.FileSuffix] = true
+			}
+		}
+	}
+
+	separator := e.nameSeparator()
+
+	var unknown []string
+	for _, name := range e.listNames() {
+		if known[name] || containsString(unknown, name) {
+			continue
+		}
+
+		for _, envPrefix := range envPrefixes {
+			rest, found := strings.CutPrefix(name, envPrefix+separator)
+			if !found {
+				continue
+			}
+
+			// The envs scoped to any other profile are not fetched, but they are
+			// still known, for ex. PREFIX_PROD_FIELD while the profile is dev
+			if e.ProfileEnv != "" {
+				if _, unscoped, found := strings.Cut(rest, separator); found && known[envPrefix+separator+unscoped] {
+					break
+				}
+			}
+
+			unknown = append(unknown, name)
+
+			break
+		}
+	}
+
+	sort.Strings(unknown)
+
+	return unknown
+}
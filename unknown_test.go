@@ -0,0 +1,74 @@
+package envstruct_test
+
+import (
+	"os"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestRejectUnknown() {
+	type Config struct {
+		Timeout  int    `tag:"timeout"`
+		Password string `tag:"password" deprecated:"pass"`
+		Database struct {
+			Host string `tag:"host"`
+		} `tag:"db"`
+	}
+
+	defer os.Clearenv()
+
+	env := envstruct.Envstruct{
+		Prefix:        "app",
+		TagName:       "tag",
+		RejectUnknown: true,
+
+		Parser: envstruct.Parser{
+			Delimiter:   ",",
+			Unmarshaler: yaml.Unmarshal,
+		},
+	}
+
+	s.Run("fails on envs with the prefix that no field uses", func() {
+		os.Clearenv()
+		os.Setenv("APP_TIMEOUTT", "30")
+		os.Setenv("APP_DB_HOTS", "localhost")
+		os.Setenv("APP_TIMEOUT", "30")
+		os.Setenv("OTHER_TIMEOUTT", "30")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "envs set for unknown fields: APP_DB_HOTS, APP_TIMEOUTT")
+		s.Zero(config.Timeout)
+	})
+
+	s.Run("knows the deprecated, file and profile envs", func() {
+		os.Clearenv()
+		os.Setenv("APP_PASS", "secret")
+		os.Setenv("APP_DB_HOST_FILE", "/dev/null")
+		os.Setenv("APP_PROFILE", "dev")
+		os.Setenv("APP_DEV_TIMEOUT", "10")
+		os.Setenv("APP_PROD_TIMEOUT", "30")
+
+		env := env
+		env.FileSuffix = "_FILE"
+		env.ProfileEnv = "APP_PROFILE"
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+		s.Equal(10, config.Timeout)
+	})
+
+	s.Run("checks every prefix", func() {
+		os.Clearenv()
+		os.Setenv("OLDAPP_TIMEOUTT", "30")
+
+		env := env
+		env.Prefixes = []string{"oldapp"}
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "envs set for unknown fields: OLDAPP_TIMEOUTT")
+	})
+}
@@ -0,0 +1,195 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Unmarshal is an UnmarshalFunc that parses strings, bools, numbers and
+// durations without pulling in a serialization library. Numbers can be
+// written with underscores between digits, for ex. "10_000_000", and integers
+// can be written in scientific notation, for ex. "1e6", as long as they are
+// whole. Structs are unmarshaled from JSON and pointers are allocated as
+// needed. Any other type, such as a map or a slice that is fetched from a
+// single env, returns an error, so an Unmarshaler such as yaml.Unmarshal should
+// be used when those are needed.
+func Unmarshal(data []byte, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("cannot unmarshal into non-pointer %T", v)
+	}
+
+	return unmarshalValue(string(data), value.Elem())
+}
+
+func unmarshalValue(data string, value reflect.Value) error {
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(data)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(value.Type().Elem())
+		err := unmarshalValue(data, elem.Elem())
+		if err != nil {
+			return err
+		}
+
+		value.Set(elem)
+
+	case reflect.String:
+		value.SetString(data)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(data)
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := parseIntLiteral(data, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := parseUintLiteral(data, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		// Underscores between digits are accepted by ParseFloat
+		f, err := strconv.ParseFloat(data, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetFloat(f)
+
+	case reflect.Struct:
+		// Structs are only fetched from a single env through the blob option,
+		// which holds a JSON document
+		err := json.Unmarshal([]byte(data), value.Addr().Interface())
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("cannot unmarshal %q into a value of type %s", data, value.Type())
+	}
+
+	return nil
+}
+
+// parseIntLiteral parses a base 10 integer that can contain underscores
+// between digits or be written in scientific notation
+func parseIntLiteral(data string, bits int) (int64, error) {
+	digits, err := removeUnderscores(data)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.ParseInt(digits, 10, bits)
+	if err == nil || !isScientific(digits) {
+		return i, err
+	}
+
+	f, err := parseWholeFloat(data)
+	if err != nil {
+		return 0, err
+	}
+
+	if f < -math.Ldexp(1, bits-1) || f >= math.Ldexp(1, bits-1) {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: data, Err: strconv.ErrRange}
+	}
+
+	return int64(f), nil
+}
+
+// parseUintLiteral is the same as parseIntLiteral for unsigned integers
+func parseUintLiteral(data string, bits int) (uint64, error) {
+	digits, err := removeUnderscores(data)
+	if err != nil {
+		return 0, err
+	}
+
+	u, err := strconv.ParseUint(digits, 10, bits)
+	if err == nil || !isScientific(digits) {
+		return u, err
+	}
+
+	f, err := parseWholeFloat(data)
+	if err != nil {
+		return 0, err
+	}
+
+	if f < 0 || f >= math.Ldexp(1, bits) {
+		return 0, &strconv.NumError{Func: "ParseUint", Num: data, Err: strconv.ErrRange}
+	}
+
+	return uint64(f), nil
+}
+
+// removeUnderscores removes the underscores between the digits of the
+// number, failing if any of them are not between two digits
+func removeUnderscores(data string) (string, error) {
+	if !strings.Contains(data, "_") {
+		return data, nil
+	}
+
+	for i, r := range data {
+		if r != '_' {
+			continue
+		}
+
+		if i == 0 || i == len(data)-1 || !isDigit(data[i-1]) || !isDigit(data[i+1]) {
+			return "", fmt.Errorf("invalid number %q, underscores need to be between digits", data)
+		}
+	}
+
+	return strings.ReplaceAll(data, "_", ""), nil
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// isScientific returns true if the number is written in scientific notation
+func isScientific(data string) bool {
+	return strings.ContainsAny(data, "eE")
+}
+
+// parseWholeFloat parses a number written in scientific notation, failing if
+// it is not a whole number
+func parseWholeFloat(data string) (float64, error) {
+	f, err := strconv.ParseFloat(data, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("invalid integer %q, it is not a whole number", data)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,47 @@
+package envstruct_test
+
+import (
+	"reflect"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestUnmarshalNumericLiterals() {
+	for _, t := range []struct {
+		raw      string
+		expected interface{}
+	}{
+		{raw: "10_000_000", expected: 10000000},
+		{raw: "-1_000", expected: -1000},
+		{raw: "1e6", expected: 1000000},
+		{raw: "1.5E3", expected: 1500},
+		{raw: "1_000e-3", expected: 1},
+		{raw: "0755", expected: 755},
+		{raw: "1_000", expected: uint16(1000)},
+		{raw: "6.5e4", expected: uint16(65000)},
+		{raw: "1e2", expected: int8(100)},
+		{raw: "1_000.5", expected: 1000.5},
+		{raw: "2.5e-3", expected: 0.0025},
+	} {
+		value, err := envstruct.ParseValue(reflect.TypeOf(t.expected), t.raw)
+		s.NoError(err, t.raw)
+		s.Equal(t.expected, value.Interface(), t.raw)
+	}
+
+	for _, t := range []struct {
+		raw       string
+		valueType interface{}
+	}{
+		{raw: "_1000", valueType: 0},
+		{raw: "1000_", valueType: 0},
+		{raw: "1__000", valueType: 0},
+		{raw: "1.5e0", valueType: 0},
+		{raw: "1e3", valueType: int8(0)},
+		{raw: "-1e3", valueType: uint(0)},
+		{raw: "7e4", valueType: uint16(0)},
+		{raw: "1e", valueType: 0},
+	} {
+		_, err := envstruct.ParseValue(reflect.TypeOf(t.valueType), t.raw)
+		s.Error(err, t.raw)
+	}
+}
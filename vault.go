@@ -0,0 +1,347 @@
+package envstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SourceVault is the name of the Vault Source
+const SourceVault = "vault"
+
+// Vault is a Source for secrets stored within HashiCorp Vault. Each env is
+// looked up as "<path>#<key>", for ex. "secret/data/app/db#password", so it is
+// meant to be wrapped with MapNames to map the env names onto paths. Both
+// versions of the KV secrets engine are supported.
+//
+// Fields can also name their secret directly through a tag, for ex.
+// `vault:"secret/data/app#db_password"`, by adding the name of the source to
+// the SourceNameTags.
+//
+// Secrets that are leased, such as database credentials, are kept and their
+// leases are renewed in the background. Once a lease can no longer be renewed
+// the secret is dropped and the OnExpire callbacks are called, so that a
+// Watcher can be reloaded with new credentials rather than leaving them to
+// silently expire (see Watch). Close stops the renewals.
+type Vault struct {
+	// Address is the address of the Vault server, for ex.
+	// "https://vault.example.com:8200"
+	Address string
+
+	// Token is the token used to authenticate with Vault
+	Token string
+
+	// Client is the HTTP client used to talk to Vault, defaulted to
+	// http.DefaultClient
+	Client *http.Client
+
+	mu       sync.Mutex
+	leased   map[string]*vaultSecret
+	onExpire []func(path string)
+
+	done      chan struct{}
+	setupOnce sync.Once
+	closeOnce sync.Once
+}
+
+// vaultSecret is a secret that was read from Vault
+type vaultSecret struct {
+	values map[string]string
+
+	leaseID       string
+	leaseDuration time.Duration
+	renewable     bool
+}
+
+// NewVault returns a Vault Source for the server at the address
+func NewVault(address string, token string) *Vault {
+	return &Vault{
+		Address: strings.TrimSuffix(address, "/"),
+		Token:   token,
+	}
+}
+
+// setup creates the leased secrets and the channel that stops the renewals, so
+// that a Vault built as a struct literal works the same as one from NewVault
+func (v *Vault) setup() {
+	v.setupOnce.Do(func() {
+		v.leased = map[string]*vaultSecret{}
+		v.done = make(chan struct{})
+	})
+}
+
+// Name returns SourceVault
+func (v *Vault) Name() string { return SourceVault }
+
+// Lookup returns the value of the key of the secret, see LookupErr
+func (v *Vault) Lookup(name string) (string, bool) {
+	value, found, _ := v.LookupErr(name)
+	return value, found
+}
+
+// LookupErr returns the value of the key of the secret at the path, with the
+// name written as "<path>#<key>". Names without a key are not found.
+func (v *Vault) LookupErr(name string) (string, bool, error) {
+	i := strings.LastIndex(name, "#")
+	if i == -1 {
+		return "", false, nil
+	}
+
+	secret, err := v.read(name[:i])
+	if err != nil || secret == nil {
+		return "", false, err
+	}
+
+	value, found := secret.values[name[i+1:]]
+	return value, found, nil
+}
+
+// OnExpire registers a callback that is called with the path of a leased
+// secret once its lease can no longer be renewed. The secret is read again the
+// next time it is looked up.
+func (v *Vault) OnExpire(callback func(path string)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.onExpire = append(v.onExpire, callback)
+}
+
+// Watch reloads the Watcher whenever the lease of a secret expires, so that
+// the struct is populated with new credentials. onReload is called with the
+// changed fields or the error that the reload failed with.
+func (v *Vault) Watch(w *Watcher, onReload func([]Change, error)) {
+	v.OnExpire(func(string) {
+		onReload(w.Reload())
+	})
+}
+
+// RenewToken looks up the Token and, if it is renewable, keeps renewing it in
+// the background once two thirds of its TTL have passed, so that long running
+// processes do not lose access to Vault. The renewals stop once the token can
+// no longer be renewed or Close is called. Tokens without a TTL, such as root
+// tokens, are never renewed.
+func (v *Vault) RenewToken() error {
+	v.setup()
+
+	var response struct {
+		Data struct {
+			TTL       int  `json:"ttl"`
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}
+
+	_, err := v.request(http.MethodGet, "auth/token/lookup-self", nil, &response)
+	if err != nil {
+		return err
+	}
+
+	if response.Data.Renewable && response.Data.TTL > 0 {
+		go v.renewToken(time.Duration(response.Data.TTL) * time.Second)
+	}
+
+	return nil
+}
+
+// renewToken will keep renewing the token once two thirds of its TTL have
+// passed, until it can no longer be renewed
+func (v *Vault) renewToken(ttl time.Duration) {
+	for {
+		select {
+		case <-v.done:
+			return
+		case <-time.After(ttl * 2 / 3):
+		}
+
+		var response struct {
+			Auth struct {
+				LeaseDuration int  `json:"lease_duration"`
+				Renewable     bool `json:"renewable"`
+			} `json:"auth"`
+		}
+
+		_, err := v.request(http.MethodPut, "auth/token/renew-self", map[string]interface{}{}, &response)
+		if err != nil || !response.Auth.Renewable || response.Auth.LeaseDuration <= 0 {
+			return
+		}
+
+		ttl = time.Duration(response.Auth.LeaseDuration) * time.Second
+	}
+}
+
+// Close stops renewing the leases of the secrets
+func (v *Vault) Close() {
+	v.setup()
+
+	v.closeOnce.Do(func() {
+		close(v.done)
+	})
+}
+
+// read returns the secret at the path, or nil if there is no secret. Leased
+// secrets are only read once and kept until their lease expires, so that all
+// of the keys of a secret, for ex. the username and password of database
+// credentials, come from the same lease.
+func (v *Vault) read(path string) (*vaultSecret, error) {
+	v.setup()
+
+	v.mu.Lock()
+	secret, found := v.leased[path]
+	v.mu.Unlock()
+
+	if found {
+		return secret, nil
+	}
+
+	var response struct {
+		LeaseID       string                 `json:"lease_id"`
+		LeaseDuration int                    `json:"lease_duration"`
+		Renewable     bool                   `json:"renewable"`
+		Data          map[string]interface{} `json:"data"`
+	}
+
+	found, err := v.request(http.MethodGet, path, nil, &response)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	data := response.Data
+
+	// Version 2 of the KV secrets engine nests the secret within the data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	secret = &vaultSecret{
+		values:        map[string]string{},
+		leaseID:       response.LeaseID,
+		leaseDuration: time.Duration(response.LeaseDuration) * time.Second,
+		renewable:     response.Renewable,
+	}
+
+	for key, value := range data {
+		secret.values[key] = flatValue(value)
+	}
+
+	if secret.leaseID != "" && secret.leaseDuration > 0 {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+
+		// The lock is not held while reading, so the secret could have been
+		// read at the same time, in which case the lease that was kept first
+		// is used so that all of the keys come from it
+		if leased, found := v.leased[path]; found {
+			return leased, nil
+		}
+
+		v.leased[path] = secret
+		go v.renew(path, secret)
+	}
+
+	return secret, nil
+}
+
+// renew will keep renewing the lease of the secret once two thirds of it have
+// passed, until it can no longer be renewed
+func (v *Vault) renew(path string, secret *vaultSecret) {
+	duration := secret.leaseDuration
+	final := !secret.renewable
+
+	for {
+		select {
+		case <-v.done:
+			return
+		case <-time.After(duration * 2 / 3):
+		}
+
+		if final {
+			v.expire(path, secret)
+			return
+		}
+
+		var response struct {
+			LeaseDuration int `json:"lease_duration"`
+		}
+
+		_, err := v.request(http.MethodPut, "sys/leases/renew", map[string]interface{}{
+			"lease_id":  secret.leaseID,
+			"increment": int(secret.leaseDuration / time.Second),
+		}, &response)
+		if err != nil {
+			v.expire(path, secret)
+			return
+		}
+
+		// If the lease was not extended by as much as was asked for, it has
+		// reached its maximum TTL and can not be renewed again
+		duration = time.Duration(response.LeaseDuration) * time.Second
+		final = duration < secret.leaseDuration
+	}
+}
+
+// expire will drop the secret so that it is read again and notify the
+// OnExpire callbacks
+func (v *Vault) expire(path string, secret *vaultSecret) {
+	v.mu.Lock()
+	if v.leased[path] == secret {
+		delete(v.leased, path)
+	}
+
+	callbacks := append([]func(string){}, v.onExpire...)
+	v.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(path)
+	}
+}
+
+// request sends a request to the Vault API and decodes the response into the
+// result. False is returned if the path does not exist.
+func (v *Vault) request(method string, path string, body interface{}, result interface{}) (bool, error) {
+	var requestBody bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&requestBody).Encode(body)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	request, err := http.NewRequest(method, v.Address+"/v1/"+strings.TrimPrefix(path, "/"), &requestBody)
+	if err != nil {
+		return false, err
+	}
+
+	request.Header.Set("X-Vault-Token", v.Token)
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		var errorResponse struct {
+			Errors []string `json:"errors"`
+		}
+
+		_ = json.NewDecoder(response.Body).Decode(&errorResponse)
+
+		return false, fmt.Errorf("vault responded to %s with %d: %s", path, response.StatusCode, strings.Join(errorResponse.Errors, ", "))
+	}
+
+	return true, json.NewDecoder(response.Body).Decode(result)
+}
@@ -0,0 +1,256 @@
+package envstruct_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+// fakeVault serves a KV v2 secret along with leased database credentials
+type fakeVault struct {
+	mu          sync.Mutex
+	reads       int
+	renews      int
+	tokenRenews int
+
+	// renewDuration is the lease duration returned by each renewal
+	renewDuration int
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("X-Vault-Token") != "token" {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errors": ["permission denied"]}`)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/secret/data/staging/app":
+		fmt.Fprint(w, `{"data": {"data": {"db_password": "hunter2"}, "metadata": {"version": 3}}}`)
+
+	case "/v1/auth/token/lookup-self":
+		fmt.Fprint(w, `{"data": {"ttl": 1, "renewable": true}}`)
+
+	case "/v1/auth/token/renew-self":
+		f.tokenRenews++
+		fmt.Fprint(w, `{"auth": {"lease_duration": 1, "renewable": true}}`)
+
+	case "/v1/secret/data/app/api":
+		fmt.Fprint(w, `{"data": {"data": {"key": "abc", "retries": 3}, "metadata": {"version": 1}}}`)
+
+	case "/v1/database/creds/app":
+		f.reads++
+		fmt.Fprintf(w, `{"lease_id": "database/creds/app/%d", "lease_duration": 1, "renewable": true, "data": {"username": "user-%d", "password": "pass-%d"}}`, f.reads, f.reads, f.reads)
+
+	case "/v1/sys/leases/renew":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		f.renews++
+		fmt.Fprintf(w, `{"lease_id": %q, "lease_duration": %d, "renewable": true}`, body["lease_id"], f.renewDuration)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors": []}`)
+	}
+}
+
+func (s *EnvstructSuite) TestVault() {
+	type Config struct {
+		API struct {
+			Key     string `tag:"key"`
+			Retries int    `tag:"retries"`
+		} `tag:"api"`
+		Database struct {
+			Username string `tag:"username"`
+			Password string `tag:"password,secret"`
+		} `tag:"db"`
+	}
+
+	defer os.Clearenv()
+
+	// Maps API_KEY onto secret/data/app/api#key and DB_USERNAME onto
+	// database/creds/app#username
+	paths := func(envName string) string {
+		parts := strings.SplitN(strings.ToLower(envName), "_", 2)
+		switch parts[0] {
+		case "api":
+			return "secret/data/app/api#" + parts[1]
+		case "db":
+			return "database/creds/app#" + parts[1]
+		}
+
+		return ""
+	}
+
+	newEnv := func(vault *envstruct.Vault) envstruct.Envstruct {
+		return envstruct.Envstruct{
+			TagName: "tag",
+			Sources: []envstruct.Source{envstruct.MapNames(vault, paths)},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+	}
+
+	s.Run("reads secrets and keeps leased secrets for their lease", func() {
+		fake := &fakeVault{renewDuration: 1}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		vault := envstruct.NewVault(server.URL, "token")
+		defer vault.Close()
+
+		env := newEnv(vault)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal("abc", config.API.Key)
+		s.Equal(3, config.API.Retries)
+		s.Equal("user-1", config.Database.Username)
+		s.Equal("pass-1", config.Database.Password)
+
+		err = env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Eventually(func() bool {
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+
+			return fake.renews > 0
+		}, 5*time.Second, 50*time.Millisecond)
+
+		fake.mu.Lock()
+		s.Equal(1, fake.reads)
+		fake.mu.Unlock()
+	})
+
+	s.Run("reloads the watcher with new credentials once the lease expires", func() {
+		// Renewals only extend the lease by less than was asked for, which
+		// means that the lease has reached its maximum TTL
+		fake := &fakeVault{renewDuration: 0}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		vault := envstruct.NewVault(server.URL, "token")
+		defer vault.Close()
+
+		var config Config
+		watcher, err := envstruct.NewWatcher(newEnv(vault), &config)
+		s.NoError(err)
+		s.Equal("user-1", config.Database.Username)
+
+		reloads := make(chan []envstruct.Change, 10)
+		vault.Watch(watcher, func(changes []envstruct.Change, err error) {
+			s.Assert().NoError(err)
+			reloads <- changes
+		})
+
+		select {
+		case changes := <-reloads:
+			s.Equal([]envstruct.Change{
+				{Name: "DB_PASSWORD", Old: "******", New: "******"},
+				{Name: "DB_USERNAME", Old: "user-1", New: "user-2"},
+			}, changes)
+		case <-time.After(5 * time.Second):
+			s.Fail("timed out waiting for the lease to expire")
+		}
+
+		watcher.RLock()
+		s.Equal("pass-2", config.Database.Password)
+		watcher.RUnlock()
+	})
+
+	s.Run("fails when vault refuses the request", func() {
+		server := httptest.NewServer(&fakeVault{})
+		defer server.Close()
+
+		vault := envstruct.NewVault(server.URL, "wrong")
+		defer vault.Close()
+
+		env := newEnv(vault)
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.EqualError(err, "failed to look up API_KEY in source vault: vault responded to secret/data/app/api with 403: permission denied")
+	})
+	s.Run("looks up fields under the name in their vault tag", func() {
+		server := httptest.NewServer(&fakeVault{})
+		defer server.Close()
+
+		vault := envstruct.NewVault(server.URL, "token")
+		defer vault.Close()
+
+		type Config struct {
+			Env      string `tag:"env"`
+			Password string `tag:"db_password" vault:"secret/data/${APP_ENV}/app#db_password"`
+			Key      string `vault:"secret/data/app/api#key"`
+		}
+
+		env := envstruct.Envstruct{
+			Prefix:         "app",
+			TagName:        "tag",
+			Sources:        []envstruct.Source{envstruct.Environment, vault},
+			SourceNameTags: []string{"vault"},
+
+			Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+		}
+
+		os.Setenv("APP_ENV", "staging")
+
+		var config Config
+		err := env.FetchEnv(&config)
+		s.NoError(err)
+
+		s.Equal(Config{Env: "staging", Password: "hunter2", Key: "abc"}, config)
+
+		os.Unsetenv("APP_ENV")
+
+		err = env.FetchEnv(&config)
+		s.EqualError(err, "env APP_ENV referenced by the vault name of field Password is not set")
+	})
+
+	s.Run("renews the token", func() {
+		fake := &fakeVault{}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		vault := envstruct.NewVault(server.URL, "token")
+		defer vault.Close()
+
+		s.NoError(vault.RenewToken())
+
+		s.Eventually(func() bool {
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+
+			return fake.tokenRenews > 1
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	s.Run("works when built as a struct literal", func() {
+		fake := &fakeVault{renewDuration: 1}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		vault := &envstruct.Vault{Address: server.URL, Token: "token"}
+		defer vault.Close()
+
+		var config Config
+		err := newEnv(vault).FetchEnv(&config)
+		s.NoError(err)
+		s.Equal("user-1", config.Database.Username)
+	})
+}
@@ -0,0 +1,96 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// SourceVCAP is the name of the VCAP Source
+const SourceVCAP = "vcap"
+
+// VCAP is a Source for apps deployed to Cloud Foundry, which exposes the
+// services bound to the app and the app itself under predictable env names so
+// that the same structs can be used everywhere.
+//
+// The credentials of each service within VCAP_SERVICES are exposed as
+// <SERVICE NAME>_<CREDENTIAL>, for ex. the "uri" credential of a service named
+// "my-db" is exposed as MY_DB_URI. If there is only one service with a label,
+// its credentials are also exposed as <LABEL>_<CREDENTIAL>, for ex.
+// POSTGRES_URI, so that the structs do not depend on the names of the service
+// instances. Nested credentials are joined with an underscore and lists of
+// values are joined with a comma.
+//
+// Each value within VCAP_APPLICATION is exposed as VCAP_APPLICATION_<KEY>, for
+// ex. VCAP_APPLICATION_SPACE_NAME.
+type VCAP struct {
+	values map[string]string
+}
+
+// NewVCAP returns a VCAP Source built from the VCAP_SERVICES and
+// VCAP_APPLICATION envs of the process.
+func NewVCAP() (*VCAP, error) {
+	return ParseVCAP(os.Getenv("VCAP_SERVICES"), os.Getenv("VCAP_APPLICATION"))
+}
+
+// ParseVCAP returns a VCAP Source built from the JSON values of VCAP_SERVICES
+// and VCAP_APPLICATION. Either of them can be empty.
+func ParseVCAP(services string, application string) (*VCAP, error) {
+	vcap := &VCAP{values: map[string]string{}}
+
+	if services != "" {
+		var parsed map[string][]struct {
+			Name        string                 `json:"name"`
+			Credentials map[string]interface{} `json:"credentials"`
+		}
+
+		err := json.Unmarshal([]byte(services), &parsed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse VCAP_SERVICES: %w", err)
+		}
+
+		for label, instances := range parsed {
+			for _, instance := range instances {
+				flatten(vcap.values, flatName(instance.Name), instance.Credentials)
+
+				if len(instances) == 1 && flatName(label) != flatName(instance.Name) {
+					flatten(vcap.values, flatName(label), instance.Credentials)
+				}
+			}
+		}
+	}
+
+	if application != "" {
+		var parsed map[string]interface{}
+
+		err := json.Unmarshal([]byte(application), &parsed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse VCAP_APPLICATION: %w", err)
+		}
+
+		flatten(vcap.values, "VCAP_APPLICATION", parsed)
+	}
+
+	return vcap, nil
+}
+
+// Name returns SourceVCAP
+func (v *VCAP) Name() string { return SourceVCAP }
+
+// Lookup returns the value exposed under the env name
+func (v *VCAP) Lookup(envName string) (string, bool) {
+	value, found := v.values[envName]
+	return value, found
+}
+
+// Names returns every env name that is exposed, sorted
+func (v *VCAP) Names() []string {
+	names := make([]string, 0, len(v.values))
+	for name := range v.values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
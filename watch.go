@@ -0,0 +1,239 @@
+package envstruct
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Watcher keeps a struct populated from the environment and allows it to be
+// reloaded while the application is running. Reads of the struct need to hold
+// the read lock of the Watcher (RLock and RUnlock) so that they do not race
+// with a reload.
+type Watcher struct {
+	env    Envstruct
+	object interface{}
+
+	// defaults is a copy of the struct before any envs were fetched into it, so
+	// that every reload starts from the same defaults. Otherwise an env that was
+	// unset would leave behind the value from the previous load.
+	defaults reflect.Value
+
+	mu sync.RWMutex
+
+	// rotations are the callbacks subscribed to the rotation of secrets
+	rotations   []*rotation
+	rotationsMu sync.Mutex
+
+	// subscriptions are the callbacks subscribed to the changes of fields
+	subscriptions   []*subscription
+	subscriptionsMu sync.Mutex
+}
+
+// Change is a single field that was changed by a reload
+type Change struct {
+	// Name is the name of the env of the field
+	Name string `json:"name"`
+
+	// Old is the value before the reload, redacted if it is a secret
+	Old interface{} `json:"old"`
+
+	// New is the value after the reload, redacted if it is a secret
+	New interface{} `json:"new"`
+}
+
+// NewWatcher will fetch the envs into the struct and return a Watcher that can
+// be used to reload it. The values that are already set on the struct are
+// kept as the defaults for every reload.
+func NewWatcher(env Envstruct, object interface{}) (*Watcher, error) {
+	v := reflect.ValueOf(object)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("failed to watch object, needs to be a pointer to a struct")
+	}
+
+	w := &Watcher{
+		env:      env,
+		object:   object,
+		defaults: deepCopy(v),
+	}
+
+	err := env.FetchEnv(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Files returns the files and directories that the sources of the Watcher are
+// read from, which can be watched to trigger a Reload whenever they change.
+func (w *Watcher) Files() []string {
+	return w.env.sourceFiles()
+}
+
+// RLock locks the struct for reading
+func (w *Watcher) RLock() {
+	w.mu.RLock()
+}
+
+// RUnlock undoes a single RLock call
+func (w *Watcher) RUnlock() {
+	w.mu.RUnlock()
+}
+
+// Reload will fetch the envs again, starting from the defaults, and set the
+// result onto the struct. Any sources that are read from files are read again
+// first. The changed fields are returned, with the values of secrets
+// redacted. If the fetch fails, the struct is left untouched.
+func (w *Watcher) Reload() ([]Change, error) {
+	start := time.Now()
+
+	// The Observer is told about sources that fail to reload, as FetchEnv is
+	// not reached to do so
+	err := w.env.reloadSources()
+	if err != nil {
+		w.env.observe(start, nil, err)
+		return nil, err
+	}
+
+	updated, err := w.env.FetchEnvCopy(w.defaults.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	diff, secrets, err := w.set(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, len(diff))
+	for i, c := range diff {
+		changes[i] = c.Change
+	}
+
+	w.notifyRotations(changes, secrets)
+	w.notifySubscriptions(diff)
+
+	return changes, nil
+}
+
+// set will set the updated struct onto the struct under the lock, returning
+// the changes along with the new values of the secrets
+func (w *Watcher) set(updated interface{}) ([]change, map[string]interface{}, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changes, err := w.env.diff(w.object, updated)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secrets, err := w.env.secretFields(updated)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reflect.ValueOf(w.object).Elem().Set(reflect.ValueOf(updated).Elem())
+
+	return changes, secrets, nil
+}
+
+// change is a Change along with the values of the field before they were
+// redacted, which are only used internally, for ex. to tell whether the
+// coalesced changes of a secret cancel each other out
+type change struct {
+	Change
+
+	old interface{}
+	new interface{}
+}
+
+// diff returns the fields that differ between the two structs. The values are
+// compared before they are redacted, so that a changed secret is still
+// reported as a change.
+func (e Envstruct) diff(before interface{}, after interface{}) ([]change, error) {
+	old, err := e.dump(before, false)
+	if err != nil {
+		return nil, err
+	}
+
+	new, err := e.dump(after, false)
+	if err != nil {
+		return nil, err
+	}
+
+	redactedOld, err := e.dump(before, true)
+	if err != nil {
+		return nil, err
+	}
+
+	redactedNew, err := e.dump(after, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range new {
+		names = append(names, name)
+	}
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	changes := []change{}
+	for _, name := range names {
+		if !reflect.DeepEqual(old[name], new[name]) {
+			changes = append(changes, change{
+				Change: Change{
+					Name: name,
+					Old:  redactedOld[name],
+					New:  redactedNew[name],
+				},
+				old: old[name],
+				new: new[name],
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// Handler returns an http.Handler that renders the current configuration as
+// JSON, with the values of secret fields redacted.
+func (w *Watcher) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w.RLock()
+		defer w.RUnlock()
+
+		w.env.Handler(w.object).ServeHTTP(rw, r)
+	})
+}
+
+// ReloadHandler returns an http.Handler that reloads the struct on a POST
+// request and responds with the changed fields as JSON, with the values of
+// secret fields redacted. This allows operators to force a config refresh
+// without signals or restarts.
+func (w *Watcher) ReloadHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			rw.Header().Set("Allow", "POST")
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		changes, err := w.Reload()
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(rw, map[string]interface{}{"changes": changes})
+	})
+}
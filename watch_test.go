@@ -0,0 +1,113 @@
+package envstruct_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+	"gopkg.in/yaml.v2"
+)
+
+func (s *EnvstructSuite) TestWatcher() {
+	type Config struct {
+		Host     string `tag:"host"`
+		Port     int    `tag:"port"`
+		Password string `tag:"password,secret"`
+	}
+
+	env := envstruct.Envstruct{
+		Prefix:  "prefix",
+		TagName: "tag",
+
+		Parser: envstruct.Parser{Unmarshaler: yaml.Unmarshal},
+	}
+
+	defer os.Clearenv()
+
+	s.Run("reloads from the defaults and returns the redacted changes", func() {
+		os.Setenv("PREFIX_HOST", "example.com")
+		os.Setenv("PREFIX_PASSWORD", "hunter2")
+
+		config := Config{Host: "localhost", Port: 80}
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+		s.Equal(Config{Host: "example.com", Port: 80, Password: "hunter2"}, config)
+
+		os.Unsetenv("PREFIX_HOST")
+		os.Setenv("PREFIX_PORT", "8080")
+		os.Setenv("PREFIX_PASSWORD", "hunter3")
+
+		changes, err := watcher.Reload()
+		s.NoError(err)
+		s.Equal([]envstruct.Change{
+			{Name: "PREFIX_HOST", Old: "example.com", New: "localhost"},
+			{Name: "PREFIX_PASSWORD", Old: "******", New: "******"},
+			{Name: "PREFIX_PORT", Old: 80, New: 8080},
+		}, changes)
+
+		s.Equal(Config{Host: "localhost", Port: 8080, Password: "hunter3"}, config)
+	})
+
+	s.Run("leaves the struct untouched if the reload fails", func() {
+		os.Clearenv()
+
+		config := Config{Host: "localhost"}
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		os.Setenv("PREFIX_PORT", "notanint")
+
+		_, err = watcher.Reload()
+		s.Error(err)
+		s.Equal(Config{Host: "localhost"}, config)
+	})
+
+	s.Run("reads file sources again on reload", func() {
+		os.Clearenv()
+
+		path := filepath.Join(s.T().TempDir(), ".env")
+		s.NoError(os.WriteFile(path, []byte("PREFIX_HOST=example.com\n"), 0600))
+
+		file, err := envstruct.ReadDotenvFile(path)
+		s.NoError(err)
+
+		fileEnv := env
+		fileEnv.Sources = []envstruct.Source{envstruct.Environment, file}
+
+		config := Config{}
+		watcher, err := envstruct.NewWatcher(fileEnv, &config)
+		s.NoError(err)
+		s.Equal([]string{path}, watcher.Files())
+
+		s.NoError(os.WriteFile(path, []byte("PREFIX_HOST=example.org\n"), 0600))
+
+		changes, err := watcher.Reload()
+		s.NoError(err)
+		s.Equal([]envstruct.Change{
+			{Name: "PREFIX_HOST", Old: "example.com", New: "example.org"},
+		}, changes)
+	})
+
+	s.Run("reloads through the reload handler", func() {
+		os.Clearenv()
+
+		config := Config{}
+		watcher, err := envstruct.NewWatcher(env, &config)
+		s.NoError(err)
+
+		os.Setenv("PREFIX_HOST", "example.com")
+
+		recorder := httptest.NewRecorder()
+		watcher.ReloadHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/reload", nil))
+
+		s.Equal(http.StatusOK, recorder.Code)
+		s.JSONEq(`{"changes": [{"name": "PREFIX_HOST", "old": "", "new": "example.com"}]}`, recorder.Body.String())
+
+		recorder = httptest.NewRecorder()
+		watcher.ReloadHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/reload", nil))
+
+		s.Equal(http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
@@ -0,0 +1,70 @@
+package envstruct
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ConfigDirs returns the standard directories that the config file of the app
+// is searched for in, in order of precedence. The user's directories come
+// before the system wide ones:
+//
+//   - $XDG_CONFIG_HOME/<app>
+//   - ~/.config/<app>
+//   - each of $XDG_CONFIG_DIRS/<app>, defaulting to /etc/xdg/<app>
+//   - /etc/<app>
+func ConfigDirs(app string) []string {
+	var dirs []string
+
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		dirs = append(dirs, filepath.Join(configHome, app))
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		dirs = append(dirs, filepath.Join(home, ".config", app))
+	}
+
+	configDirs := filepath.SplitList(os.Getenv("XDG_CONFIG_DIRS"))
+	if len(configDirs) == 0 {
+		configDirs = []string{"/etc/xdg"}
+	}
+
+	for _, configDir := range configDirs {
+		if configDir != "" {
+			dirs = append(dirs, filepath.Join(configDir, app))
+		}
+	}
+
+	dirs = append(dirs, filepath.Join("/etc", app))
+
+	// Remove any directories that are listed more than once, for ex. when
+	// XDG_CONFIG_HOME is set to ~/.config
+	var unique []string
+	for _, dir := range dirs {
+		if !containsString(unique, dir) {
+			unique = append(unique, dir)
+		}
+	}
+
+	return unique
+}
+
+// FindConfigFile searches the ConfigDirs of the app for a file with one of the
+// names, for ex. FindConfigFile("myapp", "config.json"), and returns the path
+// of the first file that exists. This gives CLI tools conventional config file
+// discovery to use with the file sources. False is returned if none of the
+// files exist, so that the config file can be optional.
+func FindConfigFile(app string, fileNames ...string) (string, bool) {
+	for _, dir := range ConfigDirs(app) {
+		for _, fileName := range fileNames {
+			path := filepath.Join(dir, fileName)
+
+			info, err := os.Stat(path)
+			if err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,57 @@
+package envstruct_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/clarafu/envstruct"
+)
+
+func (s *EnvstructSuite) TestFindConfigFile() {
+	defer os.Clearenv()
+
+	root := s.T().TempDir()
+	home := filepath.Join(root, "home")
+	configHome := filepath.Join(root, "config")
+	configDir := filepath.Join(root, "xdg")
+
+	for _, dir := range []string{
+		filepath.Join(home, ".config", "myapp"),
+		filepath.Join(configHome, "myapp"),
+		filepath.Join(configDir, "myapp"),
+	} {
+		s.NoError(os.MkdirAll(dir, 0700))
+	}
+
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", configHome)
+	os.Setenv("XDG_CONFIG_DIRS", configDir)
+
+	s.Run("lists the directories in order of precedence", func() {
+		s.Equal([]string{
+			filepath.Join(configHome, "myapp"),
+			filepath.Join(home, ".config", "myapp"),
+			filepath.Join(configDir, "myapp"),
+			"/etc/myapp",
+		}, envstruct.ConfigDirs("myapp"))
+	})
+
+	s.Run("finds the file with the highest precedence", func() {
+		s.NoError(os.WriteFile(filepath.Join(configDir, "myapp", "config.json"), []byte("{}"), 0600))
+
+		path, found := envstruct.FindConfigFile("myapp", "config.json")
+		s.True(found)
+		s.Equal(filepath.Join(configDir, "myapp", "config.json"), path)
+
+		s.NoError(os.WriteFile(filepath.Join(home, ".config", "myapp", "config.properties"), []byte(""), 0600))
+
+		path, found = envstruct.FindConfigFile("myapp", "config.json", "config.properties")
+		s.True(found)
+		s.Equal(filepath.Join(home, ".config", "myapp", "config.properties"), path)
+	})
+
+	s.Run("returns false when there is no file", func() {
+		_, found := envstruct.FindConfigFile("myapp", "missing.json")
+		s.False(found)
+	})
+}